@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// TokenProvider returns the bearer token for an attempt. It is called before
+// every attempt, so implementations can refresh expiring tokens transparently.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adapts a function to TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithTokenProvider sets "Authorization: Bearer <token>" on every attempt that
+// has no Authorization header yet.
+func WithTokenProvider(provider TokenProvider) ClientOption {
+	return WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Authorization") == "" {
+				token, err := provider.Token(req.Context())
+				if err != nil {
+					return nil, fmt.Errorf("failed to get token: %w", err)
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			return next(req)
+		}
+	})
+}
+
+// SetBasicAuth sets the Authorization header to HTTP basic authentication.
+func (rb *RequestBuilder) SetBasicAuth(username, password string) *RequestBuilder {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return rb.AddHeader("Authorization", "Basic "+credentials)
+}
+
+// SetBearerToken sets the Authorization header to a bearer token.
+func (rb *RequestBuilder) SetBearerToken(token string) *RequestBuilder {
+	return rb.AddHeader("Authorization", "Bearer "+token)
+}
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_Auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); ok {
+			w.Write([]byte("basic " + user + ":" + pass))
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if resp, _ := client.Get("/").SetBasicAuth("user", "secret").Do(); resp.Text() != "basic user:secret" {
+		t.Errorf("unexpected basic auth %q", resp.Text())
+	}
+	if resp, _ := client.Get("/").SetBearerToken("abc").Do(); resp.Text() != "Bearer abc" {
+		t.Errorf("unexpected bearer token %q", resp.Text())
+	}
+
+	calls := 0
+	client = NewClient(WithBaseURL(server.URL), WithTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+		calls++
+		return fmt.Sprintf("token-%d", calls), nil
+	})))
+	for i := 1; i <= 2; i++ {
+		resp, err := client.Get("/").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("Bearer token-%d", i); resp.Text() != want {
+			t.Errorf("expected %q, got %q", want, resp.Text())
+		}
+	}
+	if resp, _ := client.Get("/").SetBearerToken("explicit").Do(); resp.Text() != "Bearer explicit" {
+		t.Errorf("explicit token should win over provider, got %q", resp.Text())
+	}
+}
@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chhz0/go-component-base/pkg/work"
+)
+
+// Batch sends reqs with at most concurrency requests in flight, on a
+// work.Pool. Responses and errors are returned in the order of reqs, errs[i]
+// being nil when reqs[i] succeeded. Every request runs regardless of the
+// others failing, see BatchFailFast.
+func Batch(ctx context.Context, reqs []*RequestBuilder, concurrency int) ([]*Response, []error) {
+	return batch(ctx, reqs, concurrency, false)
+}
+
+// BatchFailFast is Batch canceling the outstanding requests once one fails,
+// those report the context error.
+func BatchFailFast(ctx context.Context, reqs []*RequestBuilder, concurrency int) ([]*Response, []error) {
+	return batch(ctx, reqs, concurrency, true)
+}
+
+func batch(ctx context.Context, reqs []*RequestBuilder, concurrency int, failFast bool) ([]*Response, []error) {
+	resps := make([]*Response, len(reqs))
+	errs := make([]error, len(reqs))
+	if len(reqs) == 0 {
+		return resps, errs
+	}
+	if concurrency <= 0 || concurrency > len(reqs) {
+		concurrency = len(reqs)
+	}
+
+	pool, err := work.NewPool(concurrency, len(reqs))
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return resps, errs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the pool outlives ctx so queued tasks still run and report its error
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(len(reqs))
+	for i, rb := range reqs {
+		pool.AddTask(work.NewTask(func() error {
+			defer wg.Done()
+			resps[i], errs[i] = rb.DoContext(ctx)
+			return errs[i]
+		}, func(error) {
+			if failFast {
+				cancel()
+			}
+		}))
+	}
+	wg.Wait()
+	return resps, errs
+}
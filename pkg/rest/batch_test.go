@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Batch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		select {
+		case <-time.After(20 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithErrorOnStatus())
+
+	t.Run("collect all", func(t *testing.T) {
+		var reqs []*RequestBuilder
+		for i := 0; i < 8; i++ {
+			path := "/" + strconv.Itoa(i)
+			if i == 3 {
+				path = "/fail"
+			}
+			reqs = append(reqs, client.Get(path))
+		}
+
+		resps, errs := Batch(context.Background(), reqs, 3)
+		for i := range reqs {
+			if i == 3 {
+				var httpErr *HTTPError
+				if !errors.As(errs[i], &httpErr) {
+					t.Errorf("errs[3] = %v, want *HTTPError", errs[i])
+				}
+				continue
+			}
+			if errs[i] != nil || resps[i].Text() != "/"+strconv.Itoa(i) {
+				t.Errorf("reqs[%d]: resp = %v, err = %v", i, resps[i], errs[i])
+			}
+		}
+		if maxInFlight.Load() > 3 {
+			t.Errorf("%d requests in flight, concurrency is 3", maxInFlight.Load())
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		reqs := []*RequestBuilder{client.Get("/fail")}
+		for i := 0; i < 6; i++ {
+			reqs = append(reqs, client.Get("/slow"))
+		}
+
+		_, errs := BatchFailFast(context.Background(), reqs, 1)
+		if errs[0] == nil {
+			t.Fatal("errs[0] = nil, want failure")
+		}
+		if !errors.Is(errs[len(errs)-1], context.Canceled) {
+			t.Errorf("last request err = %v, want canceled", errs[len(errs)-1])
+		}
+	})
+}
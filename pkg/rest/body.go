@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"os"
+)
+
+// SetBodyReader streams r as the request body instead of holding it in memory,
+// length is the body size or -1 if unknown (sent chunked). The body is never
+// closed by the client, closing e.g. an *os.File stays with the caller.
+// Retries and 307/308 redirects replay the body only if r implements
+// io.Seeker, otherwise the request is sent once regardless of the retry policy.
+// Replays read r through io.ReaderAt when it has it (bytes.Reader,
+// strings.Reader, *os.File), other seekers are read into memory once.
+func (rb *RequestBuilder) SetBodyReader(r io.Reader, contentType string, length int64) *RequestBuilder {
+	rb.bodyReader = newReaderBody(r, contentType, length)
+	rb.bodyType = bodyTypeReader
+	return rb
+}
+
+// SetBinaryBody sends data as an application/octet-stream body.
+func (rb *RequestBuilder) SetBinaryBody(data []byte) *RequestBuilder {
+	return rb.SetBodyReader(bytes.NewReader(data), ContentTypeBinary, int64(len(data)))
+}
+
+// SetBinaryReader streams r as an application/octet-stream body. The
+// Content-Length is taken from readers that know their size (bytes.Reader,
+// strings.Reader, bytes.Buffer, regular files), otherwise the body is sent
+// chunked.
+func (rb *RequestBuilder) SetBinaryReader(r io.Reader) *RequestBuilder {
+	return rb.SetBodyReader(r, ContentTypeBinary, readerLength(r))
+}
+
+// readerLength returns the bytes left in r, or -1 if unknown.
+func readerLength(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		fi, err := v.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return -1
+		}
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return fi.Size() - pos
+	}
+	return -1
+}
+
+// replayable reports whether the request body can be sent more than once.
+func (rb *RequestBuilder) replayable() bool {
+	switch rb.bodyType {
+	case bodyTypeReader:
+		return rb.bodyReader.seeker != nil
+	case ContentTypeMultipart:
+		for _, p := range rb.parts {
+			if p.body.seeker == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type readerBody struct {
+	r           io.Reader
+	contentType string
+	length      int64
+	seeker      io.Seeker
+	start       int64
+	used        bool
+	// replay holds the body of a seeker that is not an io.ReaderAt, read on
+	// the first open so that every open returns an independent reader.
+	replay []byte
+}
+
+func newReaderBody(r io.Reader, contentType string, length int64) *readerBody {
+	body := &readerBody{r: r, contentType: contentType, length: length}
+	if s, ok := r.(io.Seeker); ok {
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			body.seeker, body.start = s, start
+		}
+	}
+	return body
+}
+
+// open returns a new reader of the body from where it was when set. Readers
+// of a replayable body are independent of each other, so a transport still
+// reading an earlier one, e.g. after a timed out attempt, does not see the
+// next attempt's reads.
+func (b *readerBody) open() (io.ReadCloser, error) {
+	if b.seeker == nil {
+		if b.used {
+			return nil, errors.New("request body reader already consumed")
+		}
+		b.used = true
+		return io.NopCloser(b.r), nil
+	}
+	if at, ok := b.r.(io.ReaderAt); ok {
+		size := int64(math.MaxInt64) - b.start
+		if b.length >= 0 {
+			size = b.length
+		}
+		return io.NopCloser(io.NewSectionReader(at, b.start, size)), nil
+	}
+	if b.replay == nil {
+		if _, err := b.seeker.Seek(b.start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(b.r)
+		if err != nil {
+			return nil, err
+		}
+		b.replay = data
+	}
+	return io.NopCloser(bytes.NewReader(b.replay)), nil
+}
+
+func (b *readerBody) prepare(req *http.Request) {
+	switch {
+	case b.length == 0:
+		req.Body = http.NoBody
+		req.ContentLength = 0
+	case b.length > 0:
+		req.ContentLength = b.length
+	default:
+		req.ContentLength = -1
+	}
+	if b.seeker != nil {
+		req.GetBody = b.open
+	}
+}
@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_RequestBuilder_SetBodyReader(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	var lengths []int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		lengths = append(lengths, r.ContentLength)
+		if r.Header.Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		RetryStatus: []int{http.StatusServiceUnavailable},
+	}))
+
+	t.Run("seekable body is replayed", func(t *testing.T) {
+		calls.Store(0)
+		bodies, lengths = nil, nil
+
+		r := strings.NewReader("skip:payload")
+		r.Seek(5, io.SeekStart)
+		resp, err := client.Post("/upload").SetBodyReader(r, "application/octet-stream", 7).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.OK() {
+			t.Fatalf("status = %d", resp.StatusCode)
+		}
+		if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+			t.Errorf("bodies = %q, want payload twice", bodies)
+		}
+		if lengths[1] != 7 {
+			t.Errorf("ContentLength = %d, want 7", lengths[1])
+		}
+	})
+
+	t.Run("stream is sent once", func(t *testing.T) {
+		calls.Store(0)
+		bodies, lengths = nil, nil
+
+		r := io.MultiReader(bytes.NewBufferString("stream"))
+		resp, err := client.Post("/upload").SetBodyReader(r, "application/octet-stream", -1).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want 503 without retry", resp.StatusCode)
+		}
+		if len(bodies) != 1 || bodies[0] != "stream" || lengths[0] != -1 {
+			t.Errorf("bodies = %q lengths = %v", bodies, lengths)
+		}
+	})
+}
+
+func Test_RequestBuilder_SetBinary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %d %s", r.Header.Get("Content-Type"), r.ContentLength, data)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	path := filepath.Join(t.TempDir(), "blob")
+	if err := os.WriteFile(path, []byte("file-bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		name string
+		rb   *RequestBuilder
+		want string
+	}{
+		{"bytes", client.Put("/blob").SetBinaryBody([]byte{'a', 'b', 'c'}), "application/octet-stream 3 abc"},
+		{"buffer", client.Put("/blob").SetBinaryReader(bytes.NewBufferString("buf")), "application/octet-stream 3 buf"},
+		{"file", client.Put("/blob").SetBinaryReader(f), "application/octet-stream 10 file-bytes"},
+		{"stream", client.Put("/blob").SetBinaryReader(io.MultiReader(strings.NewReader("s"))), "application/octet-stream -1 s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.rb.Do()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Text() != tt.want {
+				t.Errorf("got %q, want %q", resp.Text(), tt.want)
+			}
+		})
+	}
+}
+
+// seekOnly is a seekable reader without io.ReaderAt.
+type seekOnly struct{ io.ReadSeeker }
+
+func Test_ReaderBody_OpenIndependent(t *testing.T) {
+	for name, r := range map[string]io.Reader{
+		"reader at": strings.NewReader("skip:payload"),
+		"seeker":    seekOnly{strings.NewReader("skip:payload")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r.(io.Seeker).Seek(5, io.SeekStart)
+			body := newReaderBody(r, ContentTypeBinary, -1)
+			first, err := body.open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.ReadFull(first, make([]byte, 3)); err != nil {
+				t.Fatal(err)
+			}
+			second, err := body.open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rest, _ := io.ReadAll(first)
+			all, _ := io.ReadAll(second)
+			if string(rest) != "load" || string(all) != "payload" {
+				t.Errorf("first rest = %q, second = %q, want independent readers", rest, all)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker of the target host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// BreakerSettings configures the per-host circuit breaker, zero fields use the defaults.
+type BreakerSettings struct {
+	// Window is the period over which the failure rate is measured, default 10s.
+	Window time.Duration
+	// MinRequests is the number of requests in a window before the breaker may trip, default 10.
+	MinRequests int
+	// FailureRate trips the breaker when failures/requests reaches it, default 0.5.
+	FailureRate float64
+	// OpenTimeout is how long the breaker stays open before letting trial requests through, default 30s.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of successful trial requests needed to close again, default 1.
+	HalfOpenRequests int
+	// IsFailure classifies an attempt, default: transport errors and 5xx responses.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+func (s BreakerSettings) withDefaults() BreakerSettings {
+	if s.Window <= 0 {
+		s.Window = 10 * time.Second
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = 10
+	}
+	if s.FailureRate <= 0 {
+		s.FailureRate = 0.5
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 30 * time.Second
+	}
+	if s.HalfOpenRequests <= 0 {
+		s.HalfOpenRequests = 1
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode >= http.StatusInternalServerError
+		}
+	}
+	return s
+}
+
+// breaker is the circuit breaker of a single host.
+type breaker struct {
+	settings BreakerSettings
+
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	inFlight    int    // trial requests in half-open state
+	successes   int    // successful trial requests in half-open state
+	generation  uint64 // incremented on every state change
+}
+
+// admission identifies the state a request was let through in, so results of
+// requests that outlived that state are ignored.
+type admission struct {
+	generation uint64
+}
+
+func (b *breaker) allow(now time.Time) (admission, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < b.settings.OpenTimeout {
+			return admission{}, false
+		}
+		b.setState(StateHalfOpen)
+		b.inFlight, b.successes = 0, 0
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.settings.HalfOpenRequests {
+			return admission{}, false
+		}
+		b.inFlight++
+	default:
+		if now.Sub(b.windowStart) > b.settings.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+	}
+	return admission{generation: b.generation}, true
+}
+
+func (b *breaker) record(a admission, failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if a.generation != b.generation {
+		return
+	}
+	switch b.state {
+	case StateHalfOpen:
+		b.inFlight--
+		if failed {
+			b.setState(StateOpen)
+			b.openedAt = now
+			return
+		}
+		b.successes++
+		if b.successes >= b.settings.HalfOpenRequests {
+			b.setState(StateClosed)
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+	case StateClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.settings.MinRequests &&
+			float64(b.failures)/float64(b.requests) >= b.settings.FailureRate {
+			b.setState(StateOpen)
+			b.openedAt = now
+		}
+	}
+}
+
+func (b *breaker) setState(state BreakerState) {
+	b.state = state
+	b.generation++
+}
+
+func (b *breaker) currentState() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// breakerGroup holds one breaker per host.
+type breakerGroup struct {
+	settings BreakerSettings
+	mu       sync.Mutex
+	hosts    map[string]*breaker
+}
+
+func (g *breakerGroup) get(host string) *breaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, ok := g.hosts[host]
+	if !ok {
+		b = &breaker{settings: g.settings, windowStart: time.Now()}
+		g.hosts[host] = b
+	}
+	return b
+}
+
+func (g *breakerGroup) middleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		b := g.get(req.URL.Host)
+		a, ok := b.allow(time.Now())
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.URL.Host)
+		}
+
+		resp, err := next(req)
+		b.record(a, g.settings.IsFailure(resp, err), time.Now())
+		return resp, err
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker per target host, so requests to
+// a failing host fail fast with ErrCircuitOpen instead of waiting for timeouts
+// and retries.
+func WithCircuitBreaker(settings BreakerSettings) ClientOption {
+	return func(c *Client) {
+		c.breakers = &breakerGroup{
+			settings: settings.withDefaults(),
+			hosts:    make(map[string]*breaker),
+		}
+		c.Use(c.breakers.middleware)
+	}
+}
+
+// BreakerState returns the circuit breaker state of host ("example.com:443"),
+// StateClosed if the client has no breaker or the host was never called.
+func (c *Client) BreakerState(host string) BreakerState {
+	if c.breakers == nil {
+		return StateClosed
+	}
+	return c.breakers.get(host).currentState()
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	var hits atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	client := NewClient(
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithCircuitBreaker(BreakerSettings{MinRequests: 2, OpenTimeout: 50 * time.Millisecond}),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(server.URL).Do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := client.BreakerState(u.Host); got != StateOpen {
+		t.Fatalf("expected open breaker, got %v", got)
+	}
+
+	if _, err := client.Get(server.URL).Do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("open breaker should not reach the server, got %d hits", got)
+	}
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	resp, err := client.Get(server.URL).Do()
+	if err != nil || !resp.OK() {
+		t.Fatalf("expected trial request to pass, got %v, %v", resp, err)
+	}
+	if got := client.BreakerState(u.Host); got != StateClosed {
+		t.Errorf("expected closed breaker after successful trial, got %v", got)
+	}
+}
+
+func Test_Breaker_IgnoresStaleResults(t *testing.T) {
+	b := &breaker{
+		settings:    BreakerSettings{MinRequests: 1, OpenTimeout: time.Millisecond}.withDefaults(),
+		windowStart: time.Now(),
+	}
+	now := time.Now()
+
+	slow, _ := b.allow(now) // admitted while closed, finishes late
+	failing, _ := b.allow(now)
+	b.record(failing, true, now)
+	if b.currentState() != StateOpen {
+		t.Fatalf("expected open breaker, got %v", b.currentState())
+	}
+
+	now = now.Add(time.Second)
+	trial, ok := b.allow(now)
+	if !ok || b.currentState() != StateHalfOpen {
+		t.Fatalf("expected half-open trial, got %v", b.currentState())
+	}
+
+	b.record(slow, false, now)
+	if got := b.currentState(); got != StateHalfOpen {
+		t.Errorf("stale success changed state to %v", got)
+	}
+	if _, ok := b.allow(now); ok {
+		t.Error("stale result freed the trial slot")
+	}
+
+	b.record(trial, false, now)
+	if got := b.currentState(); got != StateClosed {
+		t.Errorf("expected closed breaker after trial, got %v", got)
+	}
+}
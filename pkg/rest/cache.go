@@ -0,0 +1,333 @@
+package rest
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a response kept by a CacheStore.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Expires is when the entry stops being fresh, after it is revalidated
+	// with If-None-Match / If-Modified-Since.
+	Expires time.Time
+	// VaryHeader holds the request headers named by the response's Vary
+	// header, the entry only answers requests with the same values.
+	VaryHeader http.Header
+}
+
+// maxCachedBody is the largest response body stored by Cache.
+const maxCachedBody = 10 << 20
+
+// CacheStore keeps cached responses by key, implementations must be safe for
+// concurrent use.
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+	Delete(key string)
+}
+
+// Cache returns a Middleware caching GET responses in store, following the
+// response's Cache-Control (max-age, no-cache, no-store), Expires, ETag, Vary
+// and Last-Modified headers. Fresh entries are served without a request, stale
+// ones are revalidated and served from the cache on 304 Not Modified.
+//
+// Entries are keyed by URL. Requests carrying credentials, i.e. any header in
+// RedactedHeaders such as Authorization or Cookie, bypass the cache so that
+// responses are never shared between principals. A body is stored once the
+// caller has read all of it, so streamed responses stay streamed and bodies cut
+// short by SetMaxResponseBytes or over 10 MiB are not stored.
+//
+// Cache only sees the headers set when it runs, so credentials added by
+// middlewares after it go unnoticed. WithCache avoids that by running the
+// cache after all middlewares of the client.
+func Cache(store CacheStore) Middleware {
+	return cache(store, nil)
+}
+
+// WithCache caches the client's responses, see Cache. The cache runs after
+// every middleware, e.g. WithTokenProvider, so it decides on the request as
+// it is sent, and requests that would send cookies from the client's cookie
+// jar bypass it as well.
+func WithCache(store CacheStore) ClientOption {
+	return func(c *Client) {
+		c.cache = cache(store, func() http.CookieJar { return c.httpClient.Jar })
+	}
+}
+
+func cache(store CacheStore, jar func() http.CookieJar) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet || hasDirective(req.Header, "no-store") ||
+				hasCredentials(req, jar) {
+				return next(req)
+			}
+
+			key := req.URL.String()
+			cached, ok := store.Get(key)
+			if ok && !cached.matches(req) {
+				cached, ok = nil, false
+			}
+			if ok && !hasDirective(req.Header, "no-cache") && time.Now().Before(cached.Expires) {
+				return cached.response(req), nil
+			}
+
+			if ok {
+				req = req.Clone(req.Context())
+				if etag := cached.Header.Get("ETag"); etag != "" {
+					req.Header.Set("If-None-Match", etag)
+				}
+				if lm := cached.Header.Get("Last-Modified"); lm != "" {
+					req.Header.Set("If-Modified-Since", lm)
+				}
+			}
+
+			resp, err := next(req)
+			if err != nil {
+				return resp, err
+			}
+
+			if ok && resp.StatusCode == http.StatusNotModified {
+				resp.Body.Close()
+				for k, v := range resp.Header {
+					cached.Header[k] = v
+				}
+				cached.Expires = expiresAt(cached.Header, time.Now())
+				store.Set(key, cached)
+				return cached.response(req), nil
+			}
+
+			if resp.StatusCode != http.StatusOK || !storable(resp.Header) {
+				if ok {
+					store.Delete(key)
+				}
+				return resp, nil
+			}
+
+			entry := &CachedResponse{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Expires:    expiresAt(resp.Header, time.Now()),
+				VaryHeader: varyHeader(resp.Header, req.Header),
+			}
+			resp.Body = &cachingBody{ReadCloser: resp.Body, store: func(body []byte) {
+				entry.Body = body
+				store.Set(key, entry)
+			}}
+			return resp, nil
+		}
+	}
+}
+
+// cachingBody copies the body as it is read and stores it on EOF.
+type cachingBody struct {
+	io.ReadCloser
+	buf      bytes.Buffer
+	store    func(body []byte)
+	tooLarge bool
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if !b.tooLarge {
+		if b.buf.Len()+n > maxCachedBody {
+			b.tooLarge = true
+			b.buf = bytes.Buffer{}
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF && !b.tooLarge && b.store != nil {
+		b.store(b.buf.Bytes())
+		b.store = nil
+	}
+	return n, err
+}
+
+// hasCredentials reports whether req carries a header of RedactedHeaders or
+// would be sent with cookies from jar.
+func hasCredentials(req *http.Request, jar func() http.CookieJar) bool {
+	for k := range req.Header {
+		if RedactedHeaders[http.CanonicalHeaderKey(k)] {
+			return true
+		}
+	}
+	if jar == nil {
+		return false
+	}
+	j := jar()
+	return j != nil && len(j.Cookies(req.URL)) > 0
+}
+
+// varyHeader returns the request header values named by the Vary header of
+// a response.
+func varyHeader(resp, req http.Header) http.Header {
+	var vary http.Header
+	for _, v := range resp.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if vary == nil {
+				vary = make(http.Header)
+			}
+			vary[http.CanonicalHeaderKey(name)] = req.Values(name)
+		}
+	}
+	return vary
+}
+
+// matches reports whether req has the header values the entry varies on.
+func (c *CachedResponse) matches(req *http.Request) bool {
+	for name, values := range c.VaryHeader {
+		if strings.Join(req.Header.Values(name), ",") != strings.Join(values, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(c.StatusCode) + " " + http.StatusText(c.StatusCode),
+		StatusCode:    c.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// storable reports whether a 200 response may be kept: it must not be
+// no-store and needs either a freshness lifetime or a validator.
+func storable(h http.Header) bool {
+	if hasDirective(h, "no-store") || h.Get("Vary") == "*" {
+		return false
+	}
+	if _, ok := maxAge(h); ok {
+		return true
+	}
+	return h.Get("Expires") != "" || h.Get("ETag") != "" || h.Get("Last-Modified") != ""
+}
+
+// expiresAt returns when a response received at now stops being fresh.
+func expiresAt(h http.Header, now time.Time) time.Time {
+	if hasDirective(h, "no-cache") {
+		return now
+	}
+	if age, ok := maxAge(h); ok {
+		return now.Add(age)
+	}
+	if t, err := http.ParseTime(h.Get("Expires")); err == nil {
+		return t
+	}
+	return now
+}
+
+func maxAge(h http.Header) (time.Duration, bool) {
+	for _, d := range cacheDirectives(h) {
+		if v, ok := strings.CutPrefix(d, "max-age="); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return 0, false
+			}
+			return time.Duration(n) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+func hasDirective(h http.Header, directive string) bool {
+	for _, d := range cacheDirectives(h) {
+		if d == directive {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheDirectives(h http.Header) []string {
+	var directives []string
+	for _, v := range h.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			directives = append(directives, strings.ToLower(strings.TrimSpace(d)))
+		}
+	}
+	return directives
+}
+
+// MemoryCache is an in-memory CacheStore evicting the least recently used
+// entry beyond its capacity.
+type MemoryCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key  string
+	resp *CachedResponse
+}
+
+// NewMemoryCache creates a MemoryCache holding at most maxEntries responses,
+// 0 means unbounded.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		max:     maxEntries,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(e)
+	resp := *e.Value.(*memoryEntry).resp
+	resp.Header = resp.Header.Clone()
+	return &resp, true
+}
+
+func (m *MemoryCache) Set(key string, resp *CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*memoryEntry).resp = resp
+		m.order.MoveToFront(e)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&memoryEntry{key: key, resp: resp})
+	if m.max > 0 && m.order.Len() > m.max {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		m.order.Remove(e)
+		delete(m.entries, key)
+	}
+}
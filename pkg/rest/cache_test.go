@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Client_Cache(t *testing.T) {
+	var hits, revalidated atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidated.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Write([]byte("body of " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithCache(NewMemoryCache(10)))
+	get := func(path string) *Response {
+		t.Helper()
+		resp, err := client.Get(path).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.OK() || resp.Text() != "body of "+path {
+			t.Fatalf("unexpected response %d %q", resp.StatusCode, resp.Text())
+		}
+		return resp
+	}
+
+	tests := []struct {
+		path            string
+		wantHits        int32
+		wantRevalidated int32
+	}{
+		{"/fresh", 1, 0},
+		{"/etag", 2, 1},
+		{"/nostore", 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			hits.Store(0)
+			revalidated.Store(0)
+			get(tt.path)
+			get(tt.path)
+			if hits.Load() != tt.wantHits || revalidated.Load() != tt.wantRevalidated {
+				t.Errorf("server hits = %d, revalidated = %d, want %d, %d",
+					hits.Load(), revalidated.Load(), tt.wantHits, tt.wantRevalidated)
+			}
+		})
+	}
+}
+
+func Test_Client_CacheKeying(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		if r.URL.Path == "/vary" {
+			w.Header().Set("Vary", "Accept-Language")
+		}
+		w.Write([]byte(r.Header.Get("Authorization") + r.Header.Get("Accept-Language") + strings.Repeat("x", len(r.URL.Query().Get("pad")))))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithCache(NewMemoryCache(10)))
+	get := func(rb *RequestBuilder) string {
+		t.Helper()
+		resp, err := rb.Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Text()
+	}
+
+	t.Run("credentials", func(t *testing.T) {
+		hits.Store(0)
+		alice := get(client.Get("/me").SetBearerToken("alice"))
+		bob := get(client.Get("/me").SetBearerToken("bob"))
+		if alice != "Bearer alice" || bob != "Bearer bob" || hits.Load() != 2 {
+			t.Errorf("got %q, %q with %d hits, want requests with credentials not cached", alice, bob, hits.Load())
+		}
+	})
+
+	t.Run("vary", func(t *testing.T) {
+		hits.Store(0)
+		en := get(client.Get("/vary").AddHeader("Accept-Language", "en"))
+		de := get(client.Get("/vary").AddHeader("Accept-Language", "de"))
+		de2 := get(client.Get("/vary").AddHeader("Accept-Language", "de"))
+		if en != "en" || de != "de" || de2 != "de" || hits.Load() != 2 {
+			t.Errorf("got %q, %q, %q with %d hits", en, de, de2, hits.Load())
+		}
+	})
+
+	t.Run("max response bytes", func(t *testing.T) {
+		hits.Store(0)
+		for i := 0; i < 2; i++ {
+			_, err := client.Get("/big").AddQueryParam("pad", strings.Repeat("p", 100)).SetMaxResponseBytes(10).Do()
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("request %d: expected ErrResponseTooLarge, got %v", i+1, err)
+			}
+		}
+		if hits.Load() != 2 {
+			t.Errorf("got %d hits, want 2", hits.Load())
+		}
+	})
+}
+
+func Test_Client_CacheTokenProvider(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("secret for " + r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	store := NewMemoryCache(10)
+	newClient := func(token string) *Client {
+		// the cache is installed before the token provider
+		return NewClient(WithBaseURL(server.URL), WithCache(store),
+			WithTokenProvider(TokenProviderFunc(func(context.Context) (string, error) { return token, nil })))
+	}
+	for _, user := range []string{"alice", "bob"} {
+		resp, err := newClient(user).Get("/me").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Text() != "secret for Bearer "+user {
+			t.Errorf("%s got %q", user, resp.Text())
+		}
+	}
+	if hits.Load() != 2 {
+		t.Errorf("got %d hits, want authenticated requests not to be cached", hits.Load())
+	}
+}
+
+func Test_MemoryCache_Evict(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", &CachedResponse{})
+	c.Set("b", &CachedResponse{})
+	c.Get("a")
+	c.Set("c", &CachedResponse{})
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry b not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("entry %s evicted", key)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// WithGzipRequests gzips the body of every request of the client, see GzipBody.
+func WithGzipRequests() ClientOption {
+	return func(c *Client) {
+		c.gzip = true
+	}
+}
+
+// GzipBody compresses the request body with gzip while it is sent and sets
+// Content-Encoding: gzip. The body is streamed, so Content-Length is unknown.
+func (rb *RequestBuilder) GzipBody() *RequestBuilder {
+	rb.gzip = true
+	return rb
+}
+
+func gzipRequest(req *http.Request) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	req.Body = gzipBody(req.Body)
+	req.ContentLength = -1
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return gzipBody(body), nil
+		}
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+}
+
+// gzipBody compresses body on the fly, the goroutine ends once the transport
+// has read or closed the pipe.
+func gzipBody(body io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer body.Close()
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, body)
+		if closeErr := zw.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// Decompress returns a Middleware decoding gzip, deflate and br response
+// bodies. Go's transport only handles gzip, and only when it sets
+// Accept-Encoding itself; this middleware asks for all three unless the
+// request already sets Accept-Encoding, and decodes whatever the server sent.
+func Decompress() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Accept-Encoding") == "" {
+				req = req.Clone(req.Context())
+				req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+			}
+			resp, err := next(req)
+			if err != nil || req.Method == http.MethodHead {
+				return resp, err
+			}
+
+			encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+			var body io.ReadCloser
+			switch encoding {
+			case "gzip", "x-gzip":
+				body = &lazyReader{body: resp.Body, open: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }}
+			case "deflate":
+				body = &lazyReader{body: resp.Body, open: func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }}
+			case "br":
+				body = &lazyReader{body: resp.Body, open: func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }}
+			default:
+				return resp, nil
+			}
+
+			resp.Body = body
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+			resp.ContentLength = -1
+			resp.Uncompressed = true
+			return resp, nil
+		}
+	}
+}
+
+// WithDecompression installs the Decompress middleware on the client.
+func WithDecompression() ClientOption {
+	return WithMiddleware(Decompress())
+}
+
+// lazyReader creates the decoder on first read, so an empty or unread body
+// does not fail early.
+type lazyReader struct {
+	body io.ReadCloser
+	open func(io.Reader) (io.Reader, error)
+	r    io.Reader
+	err  error
+}
+
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.r == nil && l.err == nil {
+		l.r, l.err = l.open(l.body)
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.r.Read(p)
+}
+
+func (l *lazyReader) Close() error {
+	return l.body.Close()
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func Test_RequestBuilder_GzipBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			http.Error(w, "not gzipped", http.StatusBadRequest)
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.Copy(w, zr)
+	}))
+	defer server.Close()
+
+	payload := strings.Repeat("compress me ", 100)
+	resp, err := NewClient(WithBaseURL(server.URL)).Post("/upload").
+		SetBinaryBody([]byte(payload)).
+		GzipBody().
+		Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != payload {
+		t.Errorf("server got %q", resp.Text())
+	}
+}
+
+func Test_Client_Decompression(t *testing.T) {
+	const payload = "hello compressed world"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.TrimPrefix(r.URL.Path, "/")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), encoding) {
+			w.Write([]byte(payload))
+			return
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		var zw io.WriteCloser
+		switch encoding {
+		case "gzip":
+			zw = gzip.NewWriter(w)
+		case "deflate":
+			zw = zlib.NewWriter(w)
+		case "br":
+			zw = brotli.NewWriter(w)
+		}
+		zw.Write([]byte(payload))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithDecompression())
+	for _, encoding := range []string{"gzip", "deflate", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			resp, err := client.Get("/" + encoding).Do()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Text() != payload || resp.Headers.Get("Content-Encoding") != "" {
+				t.Errorf("body = %q, Content-Encoding = %q", resp.Text(), resp.Headers.Get("Content-Encoding"))
+			}
+		})
+	}
+
+	t.Run("manual accept-encoding", func(t *testing.T) {
+		resp, err := client.Get("/gzip").AddHeader("Accept-Encoding", "gzip").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Text() != payload {
+			t.Errorf("body = %q", resp.Text())
+		}
+	})
+}
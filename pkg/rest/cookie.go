@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// EnableCookieJar stores cookies set by responses and sends them back on later
+// requests of the client. Calling it again keeps the existing jar.
+func (c *Client) EnableCookieJar() *Client {
+	if _, ok := c.httpClient.Jar.(*persistentJar); !ok {
+		jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+		c.httpClient.Jar = &persistentJar{jar: jar, entries: make(map[string][]*http.Cookie)}
+	}
+	return c
+}
+
+// SaveCookies writes the cookies of the client's jar to path, e.g. to keep a
+// CLI session between runs. Expired cookies are dropped.
+func (c *Client) SaveCookies(path string) error {
+	jar, ok := c.httpClient.Jar.(*persistentJar)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(jar.snapshot(time.Now()))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// LoadCookies enables the cookie jar and fills it from a file written by
+// SaveCookies. A missing file is not an error.
+func (c *Client) LoadCookies(path string) error {
+	c.EnableCookieJar()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var entries map[string][]*http.Cookie
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	jar := c.httpClient.Jar.(*persistentJar)
+	for rawURL, cookies := range entries {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		jar.SetCookies(u, cookies)
+	}
+	return nil
+}
+
+// SetCookies adds cookies to this request, in addition to the client's jar.
+func (rb *RequestBuilder) SetCookies(cookies ...*http.Cookie) *RequestBuilder {
+	rb.cookies = append(rb.cookies, cookies...)
+	return rb
+}
+
+// persistentJar is a cookiejar.Jar that also remembers the cookies it was
+// given, since cookiejar.Jar cannot be enumerated.
+type persistentJar struct {
+	jar *cookiejar.Jar
+
+	mu      sync.Mutex
+	entries map[string][]*http.Cookie // by scheme://host
+}
+
+func (j *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key := u.Scheme + "://" + u.Host
+	now := time.Now()
+	for _, cookie := range cookies {
+		cookie := *cookie
+		// store MaxAge as an absolute expiry so it is not extended on load
+		if cookie.MaxAge > 0 {
+			cookie.Expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+			cookie.MaxAge = 0
+		}
+
+		stored := j.entries[key][:0]
+		for _, old := range j.entries[key] {
+			if old.Name != cookie.Name || old.Path != cookie.Path || old.Domain != cookie.Domain {
+				stored = append(stored, old)
+			}
+		}
+		if cookie.MaxAge == 0 && (cookie.Expires.IsZero() || cookie.Expires.After(now)) {
+			stored = append(stored, &cookie)
+		}
+		j.entries[key] = stored
+	}
+}
+
+func (j *persistentJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+func (j *persistentJar) snapshot(now time.Time) map[string][]*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	snapshot := make(map[string][]*http.Cookie, len(j.entries))
+	for key, cookies := range j.entries {
+		for _, cookie := range cookies {
+			if cookie.Expires.IsZero() || cookie.Expires.After(now) {
+				snapshot[key] = append(snapshot[key], cookie)
+			}
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Client_CookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", Path: "/", MaxAge: 3600})
+			http.SetCookie(w, &http.Cookie{Name: "gone", Value: "x", Path: "/", MaxAge: -1})
+		default:
+			var names string
+			for _, c := range r.Cookies() {
+				names += c.Name + "=" + c.Value + ";"
+			}
+			w.Write([]byte(names))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL)).EnableCookieJar()
+	if _, err := client.Get("/login").Do(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get("/me").SetCookies(&http.Cookie{Name: "extra", Value: "1"}).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "extra=1;session=s1;" {
+		t.Errorf("unexpected cookies %q", resp.Text())
+	}
+
+	file := filepath.Join(t.TempDir(), "cookies.json")
+	if err := client.SaveCookies(file); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewClient(WithBaseURL(server.URL))
+	if err := restored.LoadCookies(file); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = restored.Get("/me").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "session=s1;" {
+		t.Errorf("expected persisted session cookie, got %q", resp.Text())
+	}
+
+	if err := NewClient().LoadCookies(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("missing file should not be an error: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDebugBodyLimit is the number of body bytes dumped by EnableDebug.
+const DefaultDebugBodyLimit = 4096
+
+// RedactedHeaders are dumped as "[REDACTED]" by Debug, keys in canonical form.
+var RedactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Auth-Token":        true,
+}
+
+// Debug returns a Middleware writing every attempt to w: method, URL,
+// headers and up to bodyLimit bytes of the request and response bodies.
+// Secret headers listed in RedactedHeaders are masked. Bodies are dumped as
+// they are read rather than read ahead, so streamed downloads and event
+// streams stay streamed: the response body follows the response headers once
+// bodyLimit bytes were read, the body ended or it was closed.
+func Debug(w io.Writer, bodyLimit int) Middleware {
+	var mu sync.Mutex
+	write := func(buf *bytes.Buffer) {
+		mu.Lock()
+		_, _ = w.Write(buf.Bytes())
+		mu.Unlock()
+	}
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "--> %s %s", req.Method, req.URL.Redacted())
+			if attempt := Attempt(req.Context()); attempt > 1 {
+				fmt.Fprintf(&buf, " (attempt %d)", attempt)
+			}
+			buf.WriteByte('\n')
+			writeHeaders(&buf, req.Header)
+			reqBody := debugRequestBody(req, bodyLimit)
+
+			start := time.Now()
+			resp, err := next(req)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			// the transport has sent the body it read so far
+			if prefix, more := reqBody(); len(prefix) > 0 {
+				writeBody(&buf, prefix, more)
+			}
+			if err != nil {
+				fmt.Fprintf(&buf, "<-- error %s (%s): %v\n", req.URL.Redacted(), elapsed, err)
+				write(&buf)
+				return resp, err
+			}
+			fmt.Fprintf(&buf, "<-- %s %s (%s)\n", resp.Status, req.URL.Redacted(), elapsed)
+			writeHeaders(&buf, resp.Header)
+			write(&buf)
+
+			if bodyLimit > 0 && resp.Body != nil && resp.Body != http.NoBody {
+				url := req.URL.Redacted()
+				resp.Body = newDebugBody(resp.Body, bodyLimit, func(prefix []byte, more bool) {
+					if len(prefix) == 0 {
+						return
+					}
+					var buf bytes.Buffer
+					fmt.Fprintf(&buf, "<-- body %s\n", url)
+					writeBody(&buf, prefix, more)
+					write(&buf)
+				})
+			}
+			return resp, nil
+		}
+	}
+}
+
+// EnableDebug dumps requests and responses of the client to w, see Debug.
+// It must not be called concurrently with requests.
+func (c *Client) EnableDebug(w io.Writer) *Client {
+	return c.Use(Debug(w, DefaultDebugBodyLimit))
+}
+
+func writeHeaders(buf *bytes.Buffer, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := strings.Join(h[k], ", ")
+		if RedactedHeaders[http.CanonicalHeaderKey(k)] {
+			v = "[REDACTED]"
+		}
+		fmt.Fprintf(buf, "%s: %s\n", k, v)
+	}
+}
+
+func writeBody(buf *bytes.Buffer, prefix []byte, more bool) {
+	buf.WriteByte('\n')
+	buf.Write(prefix)
+	if more {
+		buf.WriteString("\n... (truncated)")
+	}
+	buf.WriteByte('\n')
+}
+
+// debugRequestBody returns a func reporting the first limit bytes of the
+// request body the transport has read so far. The body is copied as it is
+// sent rather than read ahead, e.g. through GetBody, which may share its
+// reader with Body.
+func debugRequestBody(req *http.Request, limit int) func() ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody || limit <= 0 {
+		return func() ([]byte, bool) { return nil, false }
+	}
+	b := newDebugBody(req.Body, limit, nil)
+	req.Body = b
+	return b.captured
+}
+
+// debugBody copies the first limit bytes of a body as they are read and
+// passes them to done once the limit is passed, the body ends or is closed.
+type debugBody struct {
+	io.ReadCloser
+	limit int
+	done  func(prefix []byte, more bool)
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	more     bool
+	finished bool
+}
+
+func newDebugBody(body io.ReadCloser, limit int, done func([]byte, bool)) *debugBody {
+	return &debugBody{ReadCloser: body, limit: limit, done: done}
+}
+
+func (b *debugBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	if !b.finished {
+		room := b.limit - b.buf.Len()
+		if n > room {
+			b.buf.Write(p[:room])
+			b.more = true
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	finish := !b.finished && (b.more || err != nil)
+	b.mu.Unlock()
+
+	if finish {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *debugBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *debugBody) finish() {
+	b.mu.Lock()
+	if b.finished {
+		b.mu.Unlock()
+		return
+	}
+	b.finished = true
+	prefix, more := b.buf.Bytes(), b.more
+	b.mu.Unlock()
+
+	if b.done != nil {
+		b.done(prefix, more)
+	}
+}
+
+// captured returns the bytes copied so far.
+func (b *debugBody) captured() ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes()), b.more
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Client_EnableDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(strings.Repeat("x", DefaultDebugBodyLimit+100)))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := NewClient(WithBaseURL(server.URL)).EnableDebug(&out)
+	resp, err := client.Post("/items").
+		SetBearerToken("t0ken").
+		SetJSONBody(map[string]string{"name": "demo"}).
+		Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Text()) != DefaultDebugBodyLimit+100 {
+		t.Errorf("response body cut to %d bytes by the dump", len(resp.Text()))
+	}
+
+	dump := out.String()
+	for _, want := range []string{
+		"--> POST " + server.URL + "/items",
+		"Authorization: [REDACTED]",
+		`{"name":"demo"}`,
+		"<-- 200 OK",
+		"Set-Cookie: [REDACTED]",
+		"... (truncated)",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump misses %q:\n%s", want, dump)
+		}
+	}
+	for _, secret := range []string{"t0ken", "session=secret"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("dump leaks %q", secret)
+		}
+	}
+}
+
+func Test_Client_EnableDebugStreaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var out bytes.Buffer
+	client := NewClient(WithBaseURL(server.URL)).EnableDebug(&out)
+	stop := errors.New("stop")
+	err := client.SSE(ctx, "/events", func(Event) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want the first event before the body limit is filled", err)
+	}
+	if dump := out.String(); !strings.Contains(dump, "<-- body "+server.URL+"/events\n\ndata: first") {
+		t.Errorf("dump misses the streamed body:\n%s", dump)
+	}
+}
+
+func Test_Client_EnableDebugUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		fmt.Fprint(w, len(data))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := NewClient(WithBaseURL(server.URL)).EnableDebug(&out)
+	payload := strings.Repeat("u", 10000)
+	resp, err := client.Post("/upload").SetBinaryReader(strings.NewReader(payload)).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "10000" {
+		t.Errorf("server got %s bytes, want 10000", resp.Text())
+	}
+	if !strings.Contains(out.String(), strings.Repeat("u", DefaultDebugBodyLimit)+"\n... (truncated)") {
+		t.Errorf("dump misses the request body:\n%.300s", out.String())
+	}
+}
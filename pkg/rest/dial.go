@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// The dial options below configure the client's *http.Transport and are
+// ignored for other transports. Pass them after WithTransport/WithHTTPClient.
+
+// DialContextFunc opens the connection for a request, see http.Transport.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithDialContext connects through dial, e.g. a service mesh sidecar or an
+// in-memory listener in tests.
+func WithDialContext(dial DialContextFunc) ClientOption {
+	return func(c *Client) {
+		if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+			t.DialContext = dial
+		}
+	}
+}
+
+// WithResolver resolves host names with r, e.g. a net.Resolver querying a
+// specific DNS server.
+func WithResolver(r *net.Resolver) ClientOption {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Resolver:  r,
+	}
+	return WithDialContext(dialer.DialContext)
+}
+
+// WithUnixSocket sends every request over the unix socket at path, e.g.
+// /var/run/docker.sock. The host of the request URL is ignored but still
+// required, use a base URL such as http://localhost. Proxies are disabled.
+func WithUnixSocket(path string) ClientOption {
+	var dialer net.Dialer
+	return func(c *Client) {
+		if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+			t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", path)
+			}
+			t.Proxy = nil
+		}
+	}
+}
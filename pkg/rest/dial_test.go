@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Client_WithUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "api.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("unix " + r.URL.Path))
+	}))
+	server.Listener = ln
+	server.Start()
+	defer server.Close()
+
+	t.Setenv("HTTP_PROXY", "http://proxy.invalid:3128")
+	client := NewClient(WithBaseURL("http://localhost"), WithUnixSocket(sock))
+	resp, err := client.Get("/containers/json").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "unix /containers/json" {
+		t.Errorf("got %q", resp.Text())
+	}
+}
+
+func Test_Client_WithDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	var dialed []string
+	var d net.Dialer
+	client := NewClient(WithBaseURL("http://backend.mesh"), WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return d.DialContext(ctx, network, strings.TrimPrefix(server.URL, "http://"))
+	}))
+	resp, err := client.Get("/").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "backend.mesh" || len(dialed) != 1 || dialed[0] != "backend.mesh:80" {
+		t.Errorf("host = %q, dialed = %v", resp.Text(), dialed)
+	}
+}
@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProgressFunc is called while a download is written, written counts the
+// bytes stored so far including a resumed prefix, total is -1 when the size
+// is unknown.
+type ProgressFunc func(written, total int64)
+
+// OnProgress sets the callback reporting download progress.
+func (rb *RequestBuilder) OnProgress(fn ProgressFunc) *RequestBuilder {
+	rb.progress = fn
+	return rb
+}
+
+// Download streams the response body to w instead of buffering it, the
+// returned Response has no body. Non-2xx responses are not written to w, they
+// are read as with Do.
+// Note that the client timeout also bounds reading the body, use WithTimeout
+// to raise it for large downloads.
+func (rb *RequestBuilder) Download(ctx context.Context, w io.Writer) (*Response, error) {
+	return rb.finish(rb.download(ctx, w))
+}
+
+func (rb *RequestBuilder) download(ctx context.Context, w io.Writer) (*Response, error) {
+	resp, err := rb.send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !successful(resp.StatusCode) {
+		return rb.readResponse(resp)
+	}
+	if err := rb.copyBody(w, resp, 0); err != nil {
+		return nil, err
+	}
+	return newResponse(resp, nil), nil
+}
+
+// DownloadToFile streams the response body to path. If path already holds a
+// partial download, only the missing bytes are requested with a Range header
+// and appended; a server answering 200 instead of 206 rewrites the file from
+// the start. A 416 response for a range starting at the end of the file means
+// the file is already complete, it is returned without error.
+func (rb *RequestBuilder) DownloadToFile(ctx context.Context, path string) (*Response, error) {
+	return rb.finish(rb.downloadToFile(ctx, path))
+}
+
+func (rb *RequestBuilder) downloadToFile(ctx context.Context, path string) (*Response, error) {
+	var offset int64
+	if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+		offset = fi.Size()
+	}
+	if offset > 0 && !rb.hasHeader("Range") {
+		rb.headers["Range"] = fmt.Sprintf("bytes=%d-", offset)
+		defer delete(rb.headers, "Range")
+	} else {
+		offset = 0
+	}
+
+	resp, err := rb.send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		if size, ok := contentRangeSize(resp.Header.Get("Content-Range")); ok && size == offset {
+			return newResponse(resp, nil), nil
+		}
+		return rb.readResponse(resp)
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			return nil, fmt.Errorf("unexpected content range %q for resume at %d", resp.Header.Get("Content-Range"), offset)
+		}
+		flag = os.O_WRONLY | os.O_APPEND
+	case successful(resp.StatusCode):
+		offset = 0
+	default:
+		return rb.readResponse(resp)
+	}
+
+	f, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := rb.copyBody(f, resp, offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return newResponse(resp, nil), nil
+}
+
+func (rb *RequestBuilder) copyBody(w io.Writer, resp *http.Response, offset int64) error {
+	if rb.progress != nil {
+		total := int64(-1)
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+		w = &progressWriter{w: w, written: offset, total: total, fn: rb.progress}
+	}
+	if _, err := io.Copy(w, rb.limitBody(resp)); err != nil {
+		return fmt.Errorf("failed to download response body: %w", err)
+	}
+	return nil
+}
+
+func (rb *RequestBuilder) hasHeader(key string) bool {
+	for k := range rb.headers {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func successful(code int) bool {
+	return code >= 200 && code < 300
+}
+
+// contentRangeStart parses the first byte of "bytes 100-199/200".
+func contentRangeStart(cr string) (int64, bool) {
+	spec, ok := strings.CutPrefix(cr, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
+
+// contentRangeSize parses the complete length of "bytes */200".
+func contentRangeSize(cr string) (int64, bool) {
+	_, size, ok := strings.Cut(cr, "/")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	return n, err == nil
+}
+
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	fn      ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.fn(p.written, p.total)
+	return n, err
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_RequestBuilder_Download(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "no such artifact", http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "artifact", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	t.Run("writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		var written, total int64
+		resp, err := client.Get("/artifact").OnProgress(func(w, t int64) {
+			written, total = w, t
+		}).Download(context.Background(), &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.OK() || resp.Text() != "" {
+			t.Errorf("unexpected response %d %q", resp.StatusCode, resp.Text())
+		}
+		if buf.String() != content {
+			t.Errorf("downloaded %d bytes, want %d", buf.Len(), len(content))
+		}
+		if written != int64(len(content)) || total != int64(len(content)) {
+			t.Errorf("progress = %d/%d, want %d/%d", written, total, len(content), len(content))
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		var buf bytes.Buffer
+		resp, err := client.Get("/missing").Download(context.Background(), &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusNotFound || !strings.Contains(resp.Text(), "no such artifact") {
+			t.Errorf("unexpected response %d %q", resp.StatusCode, resp.Text())
+		}
+		if buf.Len() != 0 {
+			t.Errorf("error body written to writer: %q", buf.String())
+		}
+	})
+
+	t.Run("resume file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "artifact")
+		if err := os.WriteFile(path, []byte(content[:4000]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var first int64 = -1
+		resp, err := client.Get("/artifact").OnProgress(func(w, _ int64) {
+			if first < 0 {
+				first = w
+			}
+		}).DownloadToFile(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusPartialContent {
+			t.Errorf("status = %d, want 206", resp.StatusCode)
+		}
+		if first <= 4000 {
+			t.Errorf("progress started at %d, want past the resumed 4000 bytes", first)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != content {
+			t.Errorf("file has %d bytes, want %d", len(data), len(content))
+		}
+
+		// the file is complete now, nothing left to fetch
+		if _, err := client.Get("/artifact").DownloadToFile(context.Background(), path); err != nil {
+			t.Fatal(err)
+		}
+		data, _ = os.ReadFile(path)
+		if string(data) != content {
+			t.Errorf("complete file changed to %d bytes", len(data))
+		}
+	})
+
+	t.Run("missing file keeps target untouched", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "artifact")
+		resp, err := client.Get("/missing").DownloadToFile(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", resp.StatusCode)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("file created for error response: %v", err)
+		}
+	})
+}
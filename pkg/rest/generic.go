@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GetJSON sends a GET request and decodes the JSON response into T. 4xx and
+// 5xx responses fail with *HTTPError, other non-2xx responses with a plain
+// error. A nil client uses the package default.
+func GetJSON[T any](ctx context.Context, c *Client, path string, opts ...RequestOptions) (T, error) {
+	if c == nil {
+		c = defaultClient
+	}
+	return decodeJSON[T](ctx, c.Get(path), opts...)
+}
+
+// DoJSON sends body encoded as JSON with the given method and decodes the
+// JSON response into Resp. Non-2xx responses fail as in GetJSON, an empty
+// response body leaves Resp at its zero value. A nil client uses the package
+// default.
+func DoJSON[Req, Resp any](ctx context.Context, c *Client, method, path string, body Req, opts ...RequestOptions) (Resp, error) {
+	if c == nil {
+		c = defaultClient
+	}
+	return decodeJSON[Resp](ctx, c.R(method, path).SetJSONBody(body), opts...)
+}
+
+func decodeJSON[T any](ctx context.Context, rb *RequestBuilder, opts ...RequestOptions) (T, error) {
+	var out T
+	for _, opt := range opts {
+		opt(rb)
+	}
+	rb.AddHeader("Accept", ContentTypeJSON)
+
+	// ErrorOnStatus turns 4xx and 5xx into *HTTPError
+	resp, err := rb.ErrorOnStatus().DoContext(ctx)
+	if err != nil {
+		return out, err
+	}
+	if !successful(resp.StatusCode) {
+		return out, fmt.Errorf("unexpected status %d, expected 2xx", resp.StatusCode)
+	}
+	if len(resp.body) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(resp.body, &out); err != nil {
+		return out, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GenericJSON(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/users/1":
+			json.NewEncoder(w).Encode(user{ID: 1, Name: "ann"})
+		case r.URL.Path == "/users" && r.Method == http.MethodPost:
+			var u user
+			json.NewDecoder(r.Body).Decode(&u)
+			u.ID = 2
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(u)
+		case r.URL.Path == "/users/2" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/cached":
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(WithBaseURL(server.URL))
+
+	got, err := GetJSON[user](ctx, client, "/users/:id", WithPathParams(map[string]string{"id": "1"}))
+	if err != nil || got != (user{ID: 1, Name: "ann"}) {
+		t.Errorf("GetJSON = %+v, %v", got, err)
+	}
+
+	created, err := DoJSON[user, user](ctx, client, http.MethodPost, "/users", user{Name: "bob"})
+	if err != nil || created != (user{ID: 2, Name: "bob"}) {
+		t.Errorf("DoJSON = %+v, %v", created, err)
+	}
+
+	if _, err := DoJSON[any, struct{}](ctx, client, http.MethodDelete, "/users/2", nil); err != nil {
+		t.Errorf("empty response: %v", err)
+	}
+
+	_, err = GetJSON[user](ctx, client, "/missing")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("err = %v, want 404 *HTTPError", err)
+	}
+
+	_, err = GetJSON[user](ctx, client, "/cached")
+	if err == nil || errors.As(err, &httpErr) {
+		t.Errorf("err = %v, want a non-*HTTPError for 304", err)
+	}
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+)
+
+// RetryHook is called before attempt (2 or more) is sent. req is the request
+// about to be sent and may be changed, e.g. to refresh credentials; resp and
+// err are the outcome of the previous attempt, resp having its body closed.
+type RetryHook func(attempt int, req *http.Request, resp *http.Response, err error)
+
+type hooks struct {
+	onRetry   []RetryHook
+	onSuccess []func(*Response)
+	onError   []func(error)
+}
+
+func (h hooks) clone() hooks {
+	return hooks{
+		onRetry:   slices.Clone(h.onRetry),
+		onSuccess: slices.Clone(h.onSuccess),
+		onError:   slices.Clone(h.onError),
+	}
+}
+
+func (h hooks) retry(attempt int, req *http.Request, resp *http.Response, err error) {
+	for _, fn := range h.onRetry {
+		fn(attempt, req, resp, err)
+	}
+}
+
+// OnRetry adds a hook called before every retry of the client's requests.
+// It must not be called concurrently with requests.
+func (c *Client) OnRetry(fn RetryHook) *Client {
+	c.hooks.onRetry = append(c.hooks.onRetry, fn)
+	return c
+}
+
+// OnSuccess adds a hook called with the response of every request of the
+// client that succeeded. It must not be called concurrently with requests.
+func (c *Client) OnSuccess(fn func(*Response)) *Client {
+	c.hooks.onSuccess = append(c.hooks.onSuccess, fn)
+	return c
+}
+
+// OnError adds a hook called with the error of every request of the client
+// that failed, after all retries. It must not be called concurrently with
+// requests.
+func (c *Client) OnError(fn func(error)) *Client {
+	c.hooks.onError = append(c.hooks.onError, fn)
+	return c
+}
+
+// OnRetry adds a hook called before every retry of this request, after the
+// client's hooks.
+func (rb *RequestBuilder) OnRetry(fn RetryHook) *RequestBuilder {
+	rb.hooks.onRetry = append(rb.hooks.onRetry, fn)
+	return rb
+}
+
+// OnSuccess adds a hook called with the response if this request succeeds.
+func (rb *RequestBuilder) OnSuccess(fn func(*Response)) *RequestBuilder {
+	rb.hooks.onSuccess = append(rb.hooks.onSuccess, fn)
+	return rb
+}
+
+// OnError adds a hook called with the error if this request fails.
+func (rb *RequestBuilder) OnError(fn func(error)) *RequestBuilder {
+	rb.hooks.onError = append(rb.hooks.onError, fn)
+	return rb
+}
+
+// finish runs the success or error hooks for the outcome of a request.
+func (rb *RequestBuilder) finish(resp *Response, err error) (*Response, error) {
+	if err != nil {
+		for _, fn := range rb.hooks.onError {
+			fn(err)
+		}
+		return resp, err
+	}
+	for _, fn := range rb.hooks.onSuccess {
+		fn(resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_RequestBuilder_Hooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/fail":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.Header.Get("Authorization") != "Bearer fresh":
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	var events []string
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		RetryStatus: []int{http.StatusUnauthorized},
+	}))
+	client.OnSuccess(func(resp *Response) {
+		events = append(events, fmt.Sprintf("success %d", resp.StatusCode))
+	}).OnError(func(err error) {
+		events = append(events, "error")
+	})
+
+	// refresh the token between attempts
+	_, err := client.Get("/").SetBearerToken("stale").OnRetry(func(attempt int, req *http.Request, resp *http.Response, err error) {
+		events = append(events, fmt.Sprintf("retry %d after %d", attempt, resp.StatusCode))
+		req.Header.Set("Authorization", "Bearer fresh")
+	}).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Get("/fail").ErrorOnStatus().OnError(func(err error) {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			events = append(events, fmt.Sprintf("request error %d", httpErr.StatusCode))
+		}
+	}).Do()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	want := "[retry 2 after 401 success 200 error request error 400]"
+	if fmt.Sprint(events) != want {
+		t.Errorf("events = %v\nwant %s", events, want)
+	}
+}
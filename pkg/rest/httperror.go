@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody bounds the body quoted in HTTPError.Error.
+const maxErrorBody = 256
+
+// HTTPError is returned for 4xx and 5xx responses when ErrorOnStatus is set.
+type HTTPError struct {
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+	// Result holds the value passed to SetErrorResult, filled from Body
+	// when it decoded as JSON.
+	Result interface{}
+}
+
+func (e *HTTPError) Error() string {
+	body := e.Body
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	if len(body) == 0 {
+		return fmt.Sprintf("http status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("http status %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), body)
+}
+
+// WithErrorOnStatus makes requests of the client fail with *HTTPError on 4xx
+// and 5xx responses.
+func WithErrorOnStatus() ClientOption {
+	return func(c *Client) {
+		c.errorStatus = true
+	}
+}
+
+// ErrorOnStatus makes this request fail with *HTTPError on 4xx and 5xx responses.
+func (rb *RequestBuilder) ErrorOnStatus() *RequestBuilder {
+	rb.errorStatus = true
+	return rb
+}
+
+// SetErrorResult enables ErrorOnStatus and decodes the JSON body of an error
+// response into v, e.g. the API's error envelope. v is then available as
+// HTTPError.Result.
+func (rb *RequestBuilder) SetErrorResult(v interface{}) *RequestBuilder {
+	rb.errorStatus = true
+	rb.errorResult = v
+	return rb
+}
+
+// readResponse reads the body into a Response. With ErrorOnStatus, 4xx and
+// 5xx responses are also returned as *HTTPError alongside the Response.
+func (rb *RequestBuilder) readResponse(resp *http.Response) (*Response, error) {
+	body, err := io.ReadAll(rb.limitBody(resp))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	response := newResponse(resp, body)
+	if !rb.errorStatus || resp.StatusCode < http.StatusBadRequest {
+		return response, nil
+	}
+
+	httpErr := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Headers:    response.Headers,
+		Body:       body,
+	}
+	if rb.errorResult != nil && len(body) > 0 && json.Unmarshal(body, rb.errorResult) == nil {
+		httpErr.Result = rb.errorResult
+	}
+	return response, httpErr
+}
@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_RequestBuilder_ErrorOnStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("fine"))
+			return
+		}
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"code":"invalid","message":"name is required"}`))
+	}))
+	defer server.Close()
+
+	type envelope struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		resp, err := NewClient(WithBaseURL(server.URL)).Post("/items").Do()
+		if err != nil || resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("resp = %v, err = %v", resp, err)
+		}
+	})
+
+	t.Run("client option", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL), WithErrorOnStatus())
+		if _, err := client.Get("/ok").Do(); err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Post("/items").Do()
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("err = %v, want *HTTPError", err)
+		}
+		if httpErr.StatusCode != http.StatusUnprocessableEntity || httpErr.Headers.Get("Content-Type") != ContentTypeJSON {
+			t.Errorf("unexpected error %+v", httpErr)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Errorf("response not returned with the error")
+		}
+	})
+
+	t.Run("error result", func(t *testing.T) {
+		var env envelope
+		_, err := NewClient(WithBaseURL(server.URL)).Post("/items").SetErrorResult(&env).Do()
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("err = %v, want *HTTPError", err)
+		}
+		if env.Code != "invalid" || httpErr.Result != &env {
+			t.Errorf("envelope = %+v, result = %v", env, httpErr.Result)
+		}
+	})
+}
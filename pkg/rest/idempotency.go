@@ -0,0 +1,22 @@
+package rest
+
+import "github.com/google/uuid"
+
+// IdempotencyKeyHeader is the header set by SetIdempotencyKey.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// SetIdempotencyKey sets the Idempotency-Key header, generating a random UUID
+// when key is empty. The key is fixed when set, so every retry of the request
+// carries the same key and a server supporting idempotency applies a POST at
+// most once.
+func (rb *RequestBuilder) SetIdempotencyKey(key string) *RequestBuilder {
+	if key == "" {
+		key = uuid.NewString()
+	}
+	return rb.AddHeader(IdempotencyKeyHeader, key)
+}
+
+// IdempotencyKey returns the key set with SetIdempotencyKey, or "".
+func (rb *RequestBuilder) IdempotencyKey() string {
+	return rb.headers[IdempotencyKeyHeader]
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_RequestBuilder_SetIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	rb := client.Post("/payments").
+		SetIdempotencyKey("").
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}).
+		RetryOnStatus(http.StatusServiceUnavailable)
+	if _, err := rb.Do(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 3 || len(keys[0]) != 36 {
+		t.Fatalf("keys = %q, want 3 generated UUIDs", keys)
+	}
+	for _, k := range keys {
+		if k != rb.IdempotencyKey() {
+			t.Errorf("retry sent key %q, want %q", k, rb.IdempotencyKey())
+		}
+	}
+	if got := client.Post("/payments").SetIdempotencyKey("order-1").IdempotencyKey(); got != "order-1" {
+		t.Errorf("IdempotencyKey() = %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set
+// with SetMaxResponseBytes or WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// WithMaxResponseBytes limits the response body of every request of the
+// client, see SetMaxResponseBytes.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxBody = n
+	}
+}
+
+// SetMaxResponseBytes fails the request with ErrResponseTooLarge once the
+// response body exceeds n bytes, or up front when Content-Length does. The
+// rest of the body is not read, so the connection is closed rather than
+// reused. 0 means no limit.
+func (rb *RequestBuilder) SetMaxResponseBytes(n int64) *RequestBuilder {
+	rb.maxBody = n
+	return rb
+}
+
+// limitBody returns the response body enforcing the size limit.
+func (rb *RequestBuilder) limitBody(resp *http.Response) io.Reader {
+	if rb.maxBody <= 0 {
+		return resp.Body
+	}
+	if resp.ContentLength > rb.maxBody {
+		return &maxBytesReader{err: ErrResponseTooLarge}
+	}
+	return &maxBytesReader{r: resp.Body, remaining: rb.maxBody}
+}
+
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	// read one byte past the limit to tell a body of exactly n bytes apart
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	if int64(n) <= m.remaining {
+		m.remaining -= int64(n)
+		return n, err
+	}
+	m.err = ErrResponseTooLarge
+	n, m.remaining = int(m.remaining), 0
+	return n, m.err
+}
@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_RequestBuilder_SetMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := strings.Repeat("x", 100)
+		if r.URL.Path == "/chunked" {
+			// no Content-Length, the limit is hit while reading
+			w.Write([]byte(body[:50]))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(body[50:]))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	tests := []struct {
+		name    string
+		path    string
+		limit   int64
+		wantErr bool
+	}{
+		{"no limit", "/fixed", 0, false},
+		{"exact", "/fixed", 100, false},
+		{"content length", "/fixed", 99, true},
+		{"chunked exact", "/chunked", 100, false},
+		{"chunked", "/chunked", 60, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Get(tt.path).SetMaxResponseBytes(tt.limit).Do()
+			if tt.wantErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Errorf("err = %v, want ErrResponseTooLarge", err)
+				}
+				return
+			}
+			if err != nil || len(resp.Text()) != 100 {
+				t.Errorf("resp = %v, err = %v", resp, err)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	_, err := NewClient(WithBaseURL(server.URL), WithMaxResponseBytes(10)).Get("/chunked").Download(context.Background(), &buf)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("download err = %v, want ErrResponseTooLarge", err)
+	}
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestOptions configure a request sent by the package-level helpers below
+// or by GetJSON and DoJSON.
+type RequestOptions func(*RequestBuilder)
+
+func WithPathParams(params map[string]string) RequestOptions {
+	return func(rb *RequestBuilder) {
+		for k, v := range params {
+			rb.AddPathParam(k, v)
+		}
+	}
+}
+
+func WithQueryParams(params map[string]string) RequestOptions {
+	return func(rb *RequestBuilder) {
+		for k, v := range params {
+			rb.AddQueryParam(k, v)
+		}
+	}
+}
+
+func WithRequestHeaders(headers map[string]string) RequestOptions {
+	return func(rb *RequestBuilder) {
+		for k, v := range headers {
+			rb.AddHeader(k, v)
+		}
+	}
+}
+
+func WithJSONBody(body interface{}) RequestOptions {
+	return func(rb *RequestBuilder) {
+		rb.SetJSONBody(body)
+	}
+}
+
+func WithFormData(data map[string]string) RequestOptions {
+	return func(rb *RequestBuilder) {
+		rb.SetFormData(data)
+	}
+}
+
+func WithFile(fileName, filePath string) RequestOptions {
+	return func(rb *RequestBuilder) {
+		rb.AddFile(fileName, filePath)
+	}
+}
+
+// The helpers below send a request with the default client, see SetBaseURL.
+// They share the retry policy and semantics of RequestBuilder.DoContext;
+// HEAD and TRACE requests must not carry a body.
+
+func Get(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodGet, path, opts...)
+}
+
+func Post(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodPost, path, opts...)
+}
+
+func Put(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodPut, path, opts...)
+}
+
+func Patch(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodPatch, path, opts...)
+}
+
+func Delete(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodDelete, path, opts...)
+}
+
+func Head(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodHead, path, opts...)
+}
+
+func Options(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodOptions, path, opts...)
+}
+
+func Trace(ctx context.Context, path string, opts ...RequestOptions) (*Response, error) {
+	return doRequest(ctx, http.MethodTrace, path, opts...)
+}
+
+func doRequest(ctx context.Context, method, path string, opts ...RequestOptions) (*Response, error) {
+	rb := defaultClient.R(method, path)
+	for _, opt := range opts {
+		opt(rb)
+	}
+	return rb.DoContext(ctx)
+}
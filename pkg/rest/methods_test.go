@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_MethodHelpers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.Write([]byte(r.Method + " " + r.URL.RequestURI()))
+	}))
+	defer server.Close()
+
+	baseURL := defaultClient.baseURL
+	SetBaseURL(server.URL)
+	defer SetBaseURL(baseURL)
+
+	ctx := context.Background()
+	helpers := map[string]func(context.Context, string, ...RequestOptions) (*Response, error){
+		http.MethodGet:     Get,
+		http.MethodPost:    Post,
+		http.MethodPut:     Put,
+		http.MethodPatch:   Patch,
+		http.MethodDelete:  Delete,
+		http.MethodHead:    Head,
+		http.MethodOptions: Options,
+		http.MethodTrace:   Trace,
+	}
+	for method, helper := range helpers {
+		t.Run(method, func(t *testing.T) {
+			resp, err := helper(ctx, "/items/:id",
+				WithPathParams(map[string]string{"id": "1"}),
+				WithQueryParams(map[string]string{"q": "x"}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Headers.Get("X-Method") != method {
+				t.Errorf("server saw %s", resp.Headers.Get("X-Method"))
+			}
+			want := method + " /items/1?q=x"
+			if method == http.MethodHead {
+				want = ""
+			}
+			if resp.Text() != want {
+				t.Errorf("body = %q, want %q", resp.Text(), want)
+			}
+		})
+	}
+
+	if _, err := Head(ctx, "/items", WithJSONBody(map[string]string{"a": "b"})); err == nil {
+		t.Error("HEAD with a body should fail")
+	}
+}
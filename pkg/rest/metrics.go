@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/chhz0/go-component-base/pkg/metrics"
+)
+
+// DefaultLatencyBuckets are the latency histogram buckets in seconds used by Metrics.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics returns a Middleware recording every attempt into collector:
+//
+//	rest.requests.<host>.<method>.<status>  counter
+//	rest.latency.<host>.<method>.<status>   histogram (seconds)
+//
+// status is the status class (2xx, 4xx, ...) or "error" for transport errors.
+// A name already registered with another metric type is not recorded.
+func Metrics(collector *metrics.Collector) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+
+			status := "error"
+			if err == nil {
+				status = strconv.Itoa(resp.StatusCode/100) + "xx"
+			}
+			suffix := req.URL.Host + "." + req.Method + "." + status
+
+			if c, ok := counter(collector, "rest.requests."+suffix); ok {
+				c.Inc()
+			}
+			if h, ok := histogram(collector, "rest.latency."+suffix); ok {
+				h.Observe(time.Since(start).Seconds())
+			}
+			return resp, err
+		}
+	}
+}
+
+// WithMetrics installs the Metrics middleware on the client.
+func WithMetrics(collector *metrics.Collector) ClientOption {
+	return WithMiddleware(Metrics(collector))
+}
+
+// counter returns the counter registered as name, registering it if needed;
+// ok is false when name holds another type of metric.
+func counter(collector *metrics.Collector, name string) (*metrics.CounterMetric, bool) {
+	if collector.Get(name) == nil {
+		collector.Register(metrics.NewCounter(name))
+	}
+	c, ok := collector.Get(name).(*metrics.CounterMetric)
+	return c, ok
+}
+
+// histogram is counter for latency histograms.
+func histogram(collector *metrics.Collector, name string) (*metrics.HistogramMetric, bool) {
+	if collector.Get(name) == nil {
+		collector.Register(metrics.NewHistogram(name, slices.Clone(DefaultLatencyBuckets)))
+	}
+	h, ok := collector.Get(name).(*metrics.HistogramMetric)
+	return h, ok
+}
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/chhz0/go-component-base/pkg/metrics"
+)
+
+func Test_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	collector := metrics.NewCollector()
+	client := NewClient(WithBaseURL(server.URL), WithMetrics(collector))
+	for _, path := range []string{"/a", "/b", "/missing"} {
+		if _, err := client.Get(path).Do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, _ := url.Parse(server.URL)
+	if got := collector.Get("rest.requests." + u.Host + ".GET.2xx").Value(); got != uint64(2) {
+		t.Errorf("expected 2 successful requests, got %v", got)
+	}
+	if got := collector.Get("rest.requests." + u.Host + ".GET.4xx").Value(); got != uint64(1) {
+		t.Errorf("expected 1 client error, got %v", got)
+	}
+	h, ok := collector.Get("rest.latency." + u.Host + ".GET.2xx").(*metrics.HistogramMetric)
+	if !ok || h.Count() != 2 {
+		t.Errorf("expected latency histogram with 2 observations, got %v", h)
+	}
+}
+
+func Test_Metrics_TypeClash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	name := "rest.requests." + u.Host + ".GET.2xx"
+	collector := metrics.NewCollector()
+	collector.Register(metrics.NewHistogram(name, DefaultLatencyBuckets))
+
+	client := NewClient(WithBaseURL(server.URL), WithMetrics(collector))
+	for range 2 {
+		if _, err := client.Get("/").Do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := collector.Get(name).(*metrics.HistogramMetric); !ok {
+		t.Errorf("expected %s to stay a histogram, got %T", name, collector.Get(name))
+	}
+}
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+)
+
+type attemptKey struct{}
+
+// Attempt returns the 1-based attempt number of the request carrying ctx,
+// so middlewares can tell retries apart. It returns 0 outside of a rest request.
+func Attempt(ctx context.Context) int {
+	n, _ := ctx.Value(attemptKey{}).(int)
+	return n
+}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// RoundTripFunc sends a single HTTP attempt.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps every attempt of every request sent by a Client, e.g. for
+// auth refresh, logging, tracing or metrics.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use appends middlewares to the client, the first one added is the outermost.
+// It must not be called concurrently with requests.
+func (c *Client) Use(mws ...Middleware) *Client {
+	c.middlewares = append(c.middlewares, mws...)
+	return c
+}
+
+// WithMiddleware appends middlewares to the client, see Client.Use.
+func WithMiddleware(mws ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.Use(mws...)
+	}
+}
+
+// roundTrip sends req through the middleware chain.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	send := c.rateLimitMiddleware(c.httpClient.Do)
+	if c.cache != nil {
+		send = c.cache(send)
+	}
+	return c.chain(send)(req)
+}
+
+// chain wraps send in the client's middlewares.
+func (c *Client) chain(send RoundTripFunc) RoundTripFunc {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		send = c.middlewares[i](send)
+	}
+	return send
+}
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_Client_Use(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Trace")))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Add("X-Trace", name)
+				return next(req)
+			}
+		}
+	}
+
+	client := NewClient(WithMiddleware(trace("a"))).Use(trace("b"))
+	resp, err := client.Get(server.URL).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "a,b" {
+		t.Errorf("unexpected middleware order %q", got)
+	}
+	if resp.Text() != "a" {
+		t.Errorf("expected header from middleware, got %q", resp.Text())
+	}
+}
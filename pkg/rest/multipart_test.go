@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_RequestBuilder_Multipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var out []string
+		for _, k := range []string{"name", "tag"} {
+			out = append(out, k+"="+strings.Join(r.MultipartForm.Value[k], ","))
+		}
+		for _, k := range []string{"file", "report"} {
+			for _, fh := range r.MultipartForm.File[k] {
+				f, _ := fh.Open()
+				data, _ := io.ReadAll(f)
+				f.Close()
+				out = append(out, k+":"+fh.Filename+":"+fh.Header.Get("Content-Type")+":"+string(data))
+			}
+		}
+		w.Write([]byte(strings.Join(out, "|")))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("from disk"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// SetFormData after AddFile used to switch the body back to url-encoded
+	resp, err := NewClient(WithBaseURL(server.URL)).Post("/upload").
+		AddFile("file", path).
+		SetFormData(map[string]string{"name": "demo"}).
+		AddMultipartField("tag", "x").
+		AddMultipartField("tag", "y").
+		AddMultipartReader("report", "r.csv", "text/csv", strings.NewReader("a,b")).
+		Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "name=demo|tag=x,y|file:a.txt:application/octet-stream:from disk|report:r.csv:text/csv:a,b"
+	if resp.Text() != want {
+		t.Errorf("got %q, want %q", resp.Text(), want)
+	}
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// WithOAuth2 authenticates every request with tokens from ts. Tokens are
+// cached and only fetched again once expired.
+func WithOAuth2(ts oauth2.TokenSource) ClientOption {
+	return WithTokenProvider(oauth2Provider{ts: oauth2.ReuseTokenSource(nil, ts)})
+}
+
+// WithClientCredentials authenticates every request using the OAuth2 client
+// credentials flow described by cfg. Tokens are cached like WithOAuth2 and
+// fetched with the request's context over the client's transport, so proxy,
+// TLS and timeout settings apply to the token endpoint too.
+func WithClientCredentials(cfg *clientcredentials.Config) ClientOption {
+	return func(c *Client) {
+		WithTokenProvider(&clientCredentialsProvider{cfg: cfg, client: c})(c)
+	}
+}
+
+type clientCredentialsProvider struct {
+	cfg    *clientcredentials.Config
+	client *Client
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (p *clientCredentialsProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.token.Valid() {
+		// the token request bypasses the middleware chain, which would
+		// otherwise try to authenticate it as well
+		hc := &http.Client{
+			Transport: p.client.httpClient.Transport,
+			Timeout:   p.client.httpClient.Timeout,
+		}
+		token, err := p.cfg.Token(context.WithValue(ctx, oauth2.HTTPClient, hc))
+		if err != nil {
+			return "", err
+		}
+		p.token = token
+	}
+	return p.token.AccessToken, nil
+}
+
+type oauth2Provider struct {
+	ts oauth2.TokenSource
+}
+
+func (p oauth2Provider) Token(context.Context) (string, error) {
+	token, err := p.ts.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func Test_WithClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if id, secret, _ := r.BasicAuth(); id != "id" || secret != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			issued.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"cc-token","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithClientCredentials(&clientcredentials.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     server.URL + "/token",
+	}))
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get("/api").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Text() != "Bearer cc-token" {
+			t.Errorf("unexpected Authorization %q", resp.Text())
+		}
+	}
+	if issued.Load() != 1 {
+		t.Errorf("expected token to be cached, issued %d", issued.Load())
+	}
+}
+
+func Test_WithClientCredentials_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"cc-token","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	transport := &tokenCountingTransport{}
+	// the transport is set after the option and must still be used
+	client := NewClient(WithBaseURL(server.URL), WithClientCredentials(&clientcredentials.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     server.URL + "/token",
+	}), WithTransport(transport))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Get("/api").DoContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the token request to honour the cancelled context, got %v", err)
+	}
+	transport.tokens.Store(0)
+
+	resp, err := client.Get("/api").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "Bearer cc-token" {
+		t.Errorf("unexpected Authorization %q", resp.Text())
+	}
+	if transport.tokens.Load() != 1 {
+		t.Errorf("expected token to be fetched through the client transport, got %d", transport.tokens.Load())
+	}
+}
+
+// tokenCountingTransport counts the requests to /token it sends.
+type tokenCountingTransport struct {
+	tokens atomic.Int32
+}
+
+func (t *tokenCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/token" {
+		t.tokens.Add(1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,161 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"net/url"
+	"strings"
+)
+
+// NextPageFunc returns the request for the page after resp, prev being the
+// request that fetched resp, or nil when resp was the last page.
+type NextPageFunc func(prev *RequestBuilder, resp *Response) (*RequestBuilder, error)
+
+// Paginate sends rb and the requests returned by next, each on its own
+// client, yielding every page until next returns nil, a request fails or ctx
+// is done. Non-2xx pages are yielded as *HTTPError. Breaking out of the loop
+// stops fetching.
+//
+//	for resp, err := range rest.Paginate(ctx, client.Get("/items"), rest.LinkHeader()) {
+//		...
+//	}
+func Paginate(ctx context.Context, rb *RequestBuilder, next NextPageFunc) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		for rb != nil {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			resp, err := rb.DoContext(ctx)
+			if err == nil && !successful(resp.StatusCode) {
+				err = &HTTPError{StatusCode: resp.StatusCode, Headers: resp.Headers, Body: resp.body}
+			}
+			if err != nil {
+				yield(resp, err)
+				return
+			}
+			if !yield(resp, nil) {
+				return
+			}
+
+			if rb, err = next(rb, resp); err != nil {
+				yield(nil, fmt.Errorf("next page: %w", err))
+				return
+			}
+		}
+	}
+}
+
+// LinkHeader follows the rel="next" URL of the Link header (RFC 8288), as
+// used by GitHub-style APIs. The URL replaces the query params of the
+// previous request, headers are kept.
+func LinkHeader() NextPageFunc {
+	return func(prev *RequestBuilder, resp *Response) (*RequestBuilder, error) {
+		link := nextLink(resp.Headers.Values("Link"))
+		if link == "" {
+			return nil, nil
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			return nil, err
+		}
+		if resp.FinalURL != nil {
+			u = resp.FinalURL.ResolveReference(u)
+		}
+
+		rb := prev.clone()
+		rb.url = u.String()
+		rb.queryParams = make(url.Values)
+		rb.pathParams = make(map[string]string)
+		return rb, nil
+	}
+}
+
+// Cursor sets the query param to the cursor returned for each page, stopping
+// when it is empty.
+func Cursor(param string, cursor func(*Response) (string, error)) NextPageFunc {
+	return func(prev *RequestBuilder, resp *Response) (*RequestBuilder, error) {
+		next, err := cursor(resp)
+		if err != nil || next == "" {
+			return nil, err
+		}
+		rb := prev.clone()
+		rb.queryParams.Set(param, next)
+		return rb, nil
+	}
+}
+
+// JSONCursor is a Cursor reading the cursor from the JSON body at the given
+// field path, e.g. JSONCursor("cursor", "meta", "next_cursor").
+func JSONCursor(param string, path ...string) NextPageFunc {
+	return Cursor(param, func(resp *Response) (string, error) {
+		var v interface{}
+		if err := resp.JSON(&v); err != nil {
+			return "", err
+		}
+		for _, field := range path {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return "", nil
+			}
+			v = obj[field]
+		}
+		switch c := v.(type) {
+		case string:
+			return c, nil
+		case float64:
+			return fmt.Sprint(c), nil
+		}
+		return "", nil
+	})
+}
+
+// nextLink returns the target of the rel="next" link in Link header values.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(k, "rel") && relHas(strings.Trim(v, `"`), "next") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func relHas(rel, want string) bool {
+	for _, r := range strings.Fields(rel) {
+		if strings.EqualFold(r, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone copies the request settings so the copy can be changed independently.
+func (rb *RequestBuilder) clone() *RequestBuilder {
+	c := *rb
+	c.headers = maps.Clone(rb.headers)
+	c.queryParams = cloneValues(rb.queryParams)
+	c.pathParams = maps.Clone(rb.pathParams)
+	c.formData = cloneValues(rb.formData)
+	c.files = maps.Clone(rb.files)
+	return &c
+}
+
+func cloneValues(v url.Values) url.Values {
+	c := make(url.Values, len(v))
+	for k, vs := range v {
+		c[k] = append([]string(nil), vs...)
+	}
+	return c
+}
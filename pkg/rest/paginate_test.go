@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func Test_Paginate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		switch r.URL.Path {
+		case "/link":
+			if r.Header.Get("X-Token") != "t" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if page < 3 {
+				w.Header().Set("Link", fmt.Sprintf(`</link?page=%d>; rel="next", </link?page=3>; rel="last"`, page+1))
+			}
+			fmt.Fprint(w, page)
+		case "/cursor":
+			next := ""
+			if c := r.URL.Query().Get("cursor"); c == "" {
+				next = "b"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []string{"item-" + r.URL.Query().Get("cursor")},
+				"meta":  map[string]string{"next": next},
+			})
+		case "/broken":
+			if page > 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Link", `</broken?page=2>; rel="next"`)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{}))
+
+	t.Run("link header", func(t *testing.T) {
+		var pages []string
+		for resp, err := range Paginate(ctx, client.Get("/link").AddHeader("X-Token", "t"), LinkHeader()) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages = append(pages, resp.Text())
+		}
+		if fmt.Sprint(pages) != "[1 2 3]" {
+			t.Errorf("pages = %v", pages)
+		}
+	})
+
+	t.Run("json cursor", func(t *testing.T) {
+		var items []string
+		for resp, err := range Paginate(ctx, client.Get("/cursor"), JSONCursor("cursor", "meta", "next")) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			var page struct{ Items []string }
+			resp.JSON(&page)
+			items = append(items, page.Items...)
+		}
+		if fmt.Sprint(items) != "[item- item-b]" {
+			t.Errorf("items = %v", items)
+		}
+	})
+
+	t.Run("error stops", func(t *testing.T) {
+		var n int
+		var lastErr error
+		for _, err := range Paginate(ctx, client.Get("/broken"), LinkHeader()) {
+			n++
+			lastErr = err
+		}
+		var httpErr *HTTPError
+		if n != 2 || !errors.As(lastErr, &httpErr) || httpErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf("pages = %d, last err = %v", n, lastErr)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var errs []error
+		for _, err := range Paginate(ctx, client.Get("/link").AddHeader("X-Token", "t"), LinkHeader()) {
+			errs = append(errs, err)
+			cancel()
+		}
+		if len(errs) != 2 || !errors.Is(errs[1], context.Canceled) {
+			t.Errorf("errs = %v", errs)
+		}
+	})
+}
+
+func Test_nextLink(t *testing.T) {
+	got := nextLink([]string{`<https://api/x?page=1>; rel="prev", <https://api/x?page=3>; rel="next last"`})
+	if got != "https://api/x?page=3" {
+		t.Errorf("nextLink = %q", got)
+	}
+	if got := nextLink([]string{`<https://api/x?page=1>; rel="prev"`}); got != "" {
+		t.Errorf("nextLink = %q, want none", got)
+	}
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// placeholderRe matches a {key} placeholder or a :key segment, which runs to
+// the next '/', query or fragment.
+var placeholderRe = regexp.MustCompile(`\{([^{}/]+)\}|:([^/?#]+)`)
+
+// SetPathParams sets the values of the placeholders of a path template such
+// as /users/{id}/orders/{oid}, values are path-escaped.
+func (rb *RequestBuilder) SetPathParams(params map[string]string) *RequestBuilder {
+	for k, v := range params {
+		rb.pathParams[k] = v
+	}
+	return rb
+}
+
+// expandPath replaces {key} placeholders, or the older :key form, with the
+// escaped params in a single pass, so substituted values are never expanded
+// again. Unknown params and unfilled {key} placeholders are errors, an
+// unmatched :key is left as is since ':' also appears in ports and schemes.
+func expandPath(rawURL string, params map[string]string) (string, error) {
+	used := make(map[string]bool, len(params))
+	var missing string
+	expanded := placeholderRe.ReplaceAllStringFunc(rawURL, func(m string) string {
+		key, braced := strings.CutPrefix(m, "{")
+		if braced {
+			key = strings.TrimSuffix(key, "}")
+		} else {
+			key = strings.TrimPrefix(m, ":")
+		}
+		v, ok := params[key]
+		if !ok {
+			if braced && missing == "" {
+				missing = key
+			}
+			return m
+		}
+		used[key] = true
+		return url.PathEscape(v)
+	})
+	for _, k := range slices.Sorted(maps.Keys(params)) {
+		if !used[k] {
+			return "", fmt.Errorf("path parameter %s not found in url %s", k, rawURL)
+		}
+	}
+	if missing != "" {
+		return "", fmt.Errorf("path parameter %s not set in url %s", missing, rawURL)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,34 @@
+package rest
+
+import "testing"
+
+func Test_expandPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"braces", "http://api/users/{id}/orders/{oid}", map[string]string{"id": "7", "oid": "a/b c"}, "http://api/users/7/orders/a%2Fb%20c", false},
+		{"colon", "http://api/ping/:id", map[string]string{"id": "123"}, "http://api/ping/123", false},
+		{"repeated", "/{v}/{v}", map[string]string{"v": "x"}, "/x/x", false},
+		{"colon prefix", "/items/:idx/:id", map[string]string{"id": "1", "idx": "2"}, "/items/2/1", false},
+		{"colon query", "http://api:8080/ping/:id?x=1", map[string]string{"id": "a b"}, "http://api:8080/ping/a%20b?x=1", false},
+		{"no double substitution", "/{a}/{b}", map[string]string{"a": "{b}", "b": "x"}, "/%7Bb%7D/x", false},
+		{"value looks like colon param", "/:a/:b", map[string]string{"a": ":b", "b": "x"}, "/:b/x", false},
+		{"unknown param", "/users/{id}", map[string]string{"id": "1", "other": "2"}, "", true},
+		{"unfilled placeholder", "/users/{id}/orders/{oid}", map[string]string{"id": "1"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandPath(tt.url, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrProxyUnsupported is returned by Client.SetProxy when the client's
+// transport is not an *http.Transport.
+var ErrProxyUnsupported = errors.New("proxy requires an *http.Transport")
+
+type proxyKey struct{}
+
+// SetProxy routes the client's requests through proxyURL (http, https or
+// socks5 scheme). Hosts listed in NO_PROXY still connect directly.
+// An empty proxyURL disables proxying, including proxies from the environment.
+// By default the client honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+// It must not be called concurrently with requests.
+func (c *Client) SetProxy(proxyURL string) error {
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return ErrProxyUnsupported
+	}
+
+	if proxyURL == "" {
+		c.proxy = noProxy
+	} else {
+		if _, err := parseProxyURL(proxyURL); err != nil {
+			return err
+		}
+		cfg := httpproxy.FromEnvironment()
+		cfg.HTTPProxy, cfg.HTTPSProxy = proxyURL, proxyURL
+		c.proxy = cfg.ProxyFunc()
+	}
+	transport.Proxy = c.proxyFor
+	return nil
+}
+
+// SetProxy routes this request through proxyURL regardless of NO_PROXY,
+// an empty proxyURL sends it directly. It requires the client's transport to
+// use the client proxy, which is the case unless WithTransport or
+// WithHTTPClient was used.
+func (rb *RequestBuilder) SetProxy(proxyURL string) *RequestBuilder {
+	rb.proxy = &proxyURL
+	return rb
+}
+
+// proxyFor is the Proxy func of the client's transport: a per-request proxy
+// from the context wins over the client proxy.
+func (c *Client) proxyFor(req *http.Request) (*url.URL, error) {
+	if u, ok := req.Context().Value(proxyKey{}).(*url.URL); ok {
+		if u.Host == "" {
+			return nil, nil
+		}
+		return u, nil
+	}
+	return c.proxy(req.URL)
+}
+
+func (rb *RequestBuilder) proxyContext(ctx context.Context) (context.Context, error) {
+	if rb.proxy == nil {
+		return ctx, nil
+	}
+	if *rb.proxy == "" {
+		return context.WithValue(ctx, proxyKey{}, &url.URL{}), nil
+	}
+	u, err := parseProxyURL(*rb.proxy)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, proxyKey{}, u), nil
+}
+
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy url: unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url: missing host in %q", proxyURL)
+	}
+	return u, nil
+}
+
+func noProxy(*url.URL) (*url.URL, error) {
+	return nil, nil
+}
+
+func environmentProxy(u *url.URL) (*url.URL, error) {
+	return http.ProxyFromEnvironment(&http.Request{URL: u})
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Client_SetProxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		w.Write([]byte("via proxy " + r.URL.String()))
+	}))
+	defer proxy.Close()
+
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("direct"))
+	}))
+	defer direct.Close()
+
+	t.Setenv("NO_PROXY", "skip.invalid")
+	client := NewClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	if err := client.SetProxy(proxy.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get("http://upstream.invalid/users").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Text(); got != "via proxy http://upstream.invalid/users" {
+		t.Errorf("unexpected response %q", got)
+	}
+
+	// NO_PROXY hosts bypass the proxy and fail to resolve
+	if _, err := client.Get("http://skip.invalid/").Do(); err == nil {
+		t.Error("expected NO_PROXY host to be dialed directly")
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("expected 1 proxied request, got %d", proxied.Load())
+	}
+
+	// per-request override
+	resp, err = client.Get(direct.URL).SetProxy("").Do()
+	if err != nil || resp.Text() != "direct" {
+		t.Errorf("expected direct request, got %v, %v", resp, err)
+	}
+	resp, err = NewClient().Get("http://skip.invalid/").SetProxy(proxy.URL).Do()
+	if err != nil || resp.Text() != "via proxy http://skip.invalid/" {
+		t.Errorf("expected per-request proxy to ignore NO_PROXY, got %v, %v", resp, err)
+	}
+
+	if err := client.SetProxy("ftp://proxy"); err == nil {
+		t.Error("expected invalid scheme error")
+	}
+	if err := NewClient(WithTransport(http.DefaultTransport.(*http.Transport).Clone())).SetProxy(proxy.URL); err != nil {
+		t.Errorf("expected *http.Transport to be supported, got %v", err)
+	}
+}
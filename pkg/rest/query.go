@@ -0,0 +1,213 @@
+package rest
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetQueryStruct adds the exported fields of the struct v as query params. The
+// key comes from the `url` tag, then the `form` tag, then the field name; "-"
+// skips the field. Tag options:
+//
+//	omitempty  skip zero values
+//	unix       encode a time.Time as Unix seconds
+//
+// A `layout` tag sets the time.Time format (RFC 3339 by default). Slices and
+// arrays add one value per element, nil pointers are skipped, embedded
+// structs are flattened and other struct fields are encoded as name.field.
+// Encoding errors are returned when the request is sent.
+func (rb *RequestBuilder) SetQueryStruct(v interface{}) *RequestBuilder {
+	values, err := structValues(v, queryTags)
+	if err != nil {
+		rb.err = fmt.Errorf("query struct: %w", err)
+		return rb
+	}
+	for k, vs := range values {
+		for _, s := range vs {
+			rb.queryParams.Add(k, s)
+		}
+	}
+	return rb
+}
+
+// queryTags and formTags are the struct tags read by SetQueryStruct and
+// SetFormStruct, in order of precedence.
+var (
+	queryTags = []string{"url", "form"}
+	formTags  = []string{"form", "url"}
+)
+
+// SetFormStruct sets the exported fields of the struct v as the form body,
+// encoded like SetQueryStruct but preferring the `form` tag over `url`.
+// It can be combined with SetFormData, and with files for multipart bodies.
+func (rb *RequestBuilder) SetFormStruct(v interface{}) *RequestBuilder {
+	values, err := structValues(v, formTags)
+	if err != nil {
+		rb.err = fmt.Errorf("form struct: %w", err)
+		return rb
+	}
+	for k, vs := range values {
+		for _, s := range vs {
+			rb.formData.Add(k, s)
+		}
+	}
+	if rb.bodyType != ContentTypeMultipart {
+		rb.bodyType = ContentTypeForm
+	}
+	return rb
+}
+
+// structValues encodes the struct v into url.Values, naming fields after the
+// first of tags present.
+func structValues(v interface{}, tags []string) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %s", rv.Type())
+	}
+
+	values := make(url.Values)
+	return values, reflectValues(values, rv, "", tags)
+}
+
+func reflectValues(values url.Values, rv reflect.Value, prefix string, tags []string) error {
+	typ := rv.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts := fieldTag(field, tags)
+		if name == "-" {
+			continue
+		}
+		name = prefix + name
+
+		fv := rv.Field(i)
+		// checked before dereferencing, so a pointer can send a zero value
+		if opts.has("omitempty") && fv.IsZero() {
+			continue
+		}
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Pointer {
+			continue
+		}
+
+		if fv.Kind() == reflect.Struct && !isScalar(fv) {
+			// embedded structs are flattened, named ones nested as name.field
+			nested := prefix
+			if !field.Anonymous {
+				nested = name + "."
+			}
+			if err := reflectValues(values, fv, nested, tags); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+			if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8 {
+				values.Add(name, string(fv.Bytes()))
+				continue
+			}
+			for j := 0; j < fv.Len(); j++ {
+				s, err := queryScalar(fv.Index(j), field, opts)
+				if err != nil {
+					return err
+				}
+				values.Add(name, s)
+			}
+			continue
+		}
+
+		s, err := queryScalar(fv, field, opts)
+		if err != nil {
+			return err
+		}
+		values.Add(name, s)
+	}
+	return nil
+}
+
+func queryScalar(v reflect.Value, field reflect.StructField, opts tagOptions) (string, error) {
+	if t, ok := v.Interface().(time.Time); ok {
+		if opts.has("unix") {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		layout := field.Tag.Get("layout")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout), nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("field %s: unsupported type %s", field.Name, v.Type())
+}
+
+// isScalar reports whether a struct value encodes as a single value.
+func isScalar(v reflect.Value) bool {
+	if _, ok := v.Interface().(time.Time); ok {
+		return true
+	}
+	_, ok := v.Interface().(encoding.TextMarshaler)
+	return ok
+}
+
+type tagOptions []string
+
+func (o tagOptions) has(opt string) bool {
+	for _, s := range o {
+		if s == opt {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldTag(field reflect.StructField, tags []string) (string, tagOptions) {
+	for _, tagName := range tags {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if name == "" {
+			name = field.Name
+		}
+		return name, tagOptions(parts[1:])
+	}
+	return field.Name, nil
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_structValues(t *testing.T) {
+	type Page struct {
+		Page int `url:"page,omitempty"`
+		Size int `url:"size"`
+	}
+	type filter struct {
+		Page
+		Name     string    `url:"name"`
+		Tags     []string  `url:"tag"`
+		Active   *bool     `url:"active,omitempty"`
+		Empty    string    `url:"empty,omitempty"`
+		Skip     string    `url:"-"`
+		Since    time.Time `url:"since" layout:"2006-01-02"`
+		Until    time.Time `form:"until,unix"`
+		Score    float64   `form:"score"`
+		NilPtr   *int      `url:"nil_ptr"`
+		internal string
+	}
+
+	no := false
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	got, err := structValues(&filter{
+		Page:     Page{Size: 20},
+		Name:     "a b",
+		Tags:     []string{"x", "y"},
+		Active:   &no,
+		Skip:     "s",
+		Since:    at,
+		Until:    at,
+		Score:    1.5,
+		internal: "i",
+	}, queryTags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"size":   {"20"},
+		"name":   {"a b"},
+		"tag":    {"x", "y"},
+		"active": {"false"},
+		"since":  {"2024-05-01"},
+		"until":  {"1714564800"},
+		"score":  {"1.5"},
+	}
+	if got.Encode() != want.Encode() {
+		t.Errorf("got %s\nwant %s", got.Encode(), want.Encode())
+	}
+
+	if _, err := structValues(map[string]string{}, queryTags); err == nil {
+		t.Error("expected error for non-struct")
+	}
+	if _, err := structValues(struct{ M map[string]int }{M: map[string]int{"a": 1}}, queryTags); err == nil {
+		t.Error("expected error for map field")
+	}
+}
+
+func Test_RequestBuilder_SetFormStruct(t *testing.T) {
+	type address struct {
+		City string `form:"city"`
+		Zip  string `form:"zip,omitempty"`
+	}
+	type signup struct {
+		Name    string   `form:"name" url:"ignored"`
+		Roles   []string `form:"role"`
+		Address address  `form:"address"`
+		Billing *address `form:"billing"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != ContentTypeForm {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		r.ParseForm()
+		w.Write([]byte(r.PostForm.Encode()))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(WithBaseURL(server.URL)).Post("/signup").SetFormStruct(signup{
+		Name:    "ann",
+		Roles:   []string{"admin", "dev"},
+		Address: address{City: "Paris"},
+	}).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "address.city=Paris&name=ann&role=admin&role=dev"
+	if resp.Text() != want {
+		t.Errorf("form = %s, want %s", resp.Text(), want)
+	}
+}
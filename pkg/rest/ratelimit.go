@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by a fail-fast rate limiter when no token is available.
+var ErrRateLimited = errors.New("client rate limit exceeded")
+
+type rateLimitOptions struct {
+	perHost  bool
+	failFast bool
+}
+
+type RateLimitOption func(*rateLimitOptions)
+
+// PerHost keeps a separate token bucket for every target host.
+func PerHost() RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.perHost = true
+	}
+}
+
+// FailFast returns ErrRateLimited instead of waiting for a token.
+func FailFast() RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.failFast = true
+	}
+}
+
+// SetRateLimit limits the client to rps requests per second with bursts of up
+// to burst requests, counting every attempt. By default callers wait for a
+// token, bounded by the request context. rps <= 0 removes the limit.
+// The limit applies after the client's middlewares and may be changed while
+// requests are in flight.
+func (c *Client) SetRateLimit(rps float64, burst int, opts ...RateLimitOption) *Client {
+	if rps <= 0 {
+		c.limiter.Store(nil)
+		return c
+	}
+
+	o := &rateLimitOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	c.limiter.Store(&rateLimiter{
+		rate:    rps,
+		burst:   float64(max(burst, 1)),
+		opts:    o,
+		buckets: make(map[string]*tokenBucket),
+	})
+	return c
+}
+
+// rateLimitMiddleware is installed on every client by roundTrip, it waits for
+// a token while a limit is set.
+func (c *Client) rateLimitMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if l := c.limiter.Load(); l != nil {
+			if err := l.wait(req.Context(), req.URL.Host); err != nil {
+				return nil, err
+			}
+		}
+		return next(req)
+	}
+}
+
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	opts  *rateLimitOptions
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func (l *rateLimiter) bucket(host string) *tokenBucket {
+	if !l.opts.perHost {
+		host = ""
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &tokenBucket{rate: l.rate, burst: l.burst, tokens: l.burst, last: time.Now()}
+		l.buckets[host] = b
+	}
+	return b
+}
+
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+	b := l.bucket(host)
+	if l.opts.failFast {
+		if !b.take(time.Now()) {
+			return fmt.Errorf("%w: %s", ErrRateLimited, host)
+		}
+		return nil
+	}
+
+	d := b.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// tokenBucket refills rate tokens per second up to burst.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// take consumes a token if one is available.
+func (b *tokenBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// reserve consumes a token and returns how long to wait until it is available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Client_SetRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient().SetRateLimit(20, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Get(server.URL).Do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// burst of 2, then two more tokens at 20 rps
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected requests to be throttled, took %v", elapsed)
+	}
+
+	client.SetRateLimit(1, 1, FailFast(), PerHost())
+	if _, err := client.Get(server.URL).Do(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(server.URL).Do(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+
+	client.SetRateLimit(0, 0)
+	if _, err := client.Get(server.URL).Do(); err != nil {
+		t.Errorf("expected limit to be removed, got %v", err)
+	}
+}
+
+func Test_TokenBucket(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{rate: 10, burst: 1, tokens: 1, last: now}
+	if d := b.reserve(now); d != 0 {
+		t.Errorf("expected immediate token, got %v", d)
+	}
+	if d := b.reserve(now); d != 100*time.Millisecond {
+		t.Errorf("expected 100ms wait, got %v", d)
+	}
+	b.cancel()
+	if b.take(now) {
+		t.Error("expected no token available")
+	}
+	if !b.take(now.Add(100 * time.Millisecond)) {
+		t.Error("expected token after refill")
+	}
+}
+
+func Test_Client_SetRateLimitConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if _, err := client.Get(server.URL).Do(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	// changing the limit while requests are in flight is safe
+	client.SetRateLimit(1000, 10)
+	client.SetRateLimit(0, 0)
+	wg.Wait()
+}
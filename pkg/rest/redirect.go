@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// defaultMaxRedirects is the redirect limit of net/http, kept by
+// SetRedirectPolicy unless MaxRedirects sets another.
+const defaultMaxRedirects = 10
+
+// RedirectPolicy decides whether to follow the redirect to req, via holds the
+// requests made so far, oldest first. It has the semantics of
+// http.Client.CheckRedirect: returning http.ErrUseLastResponse stops following
+// and returns the redirect response itself, any other error fails the request.
+type RedirectPolicy interface {
+	CheckRedirect(req *http.Request, via []*http.Request) error
+}
+
+// RedirectPolicyFunc adapts a function to RedirectPolicy.
+type RedirectPolicyFunc func(req *http.Request, via []*http.Request) error
+
+func (f RedirectPolicyFunc) CheckRedirect(req *http.Request, via []*http.Request) error {
+	return f(req, via)
+}
+
+// NoRedirects returns redirect responses instead of following them.
+func NoRedirects() RedirectPolicy {
+	return RedirectPolicyFunc(func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	})
+}
+
+// MaxRedirects fails requests redirected more than n times. With
+// SetRedirectPolicy it replaces the default limit of 10, so n may be higher.
+func MaxRedirects(n int) RedirectPolicy {
+	return maxRedirects(n)
+}
+
+type maxRedirects int
+
+func (n maxRedirects) CheckRedirect(_ *http.Request, via []*http.Request) error {
+	if len(via) > int(n) {
+		return fmt.Errorf("stopped after %d redirects", int(n))
+	}
+	return nil
+}
+
+// SameHostOnly does not follow redirects to another host and returns the
+// redirect response instead.
+func SameHostOnly() RedirectPolicy {
+	return RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	})
+}
+
+// StripHeaders removes the given headers when a redirect leaves the original
+// host. net/http already strips Authorization, Cookie and WWW-Authenticate,
+// use this for custom credentials such as API key headers.
+func StripHeaders(headers ...string) RedirectPolicy {
+	return RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host {
+			for _, h := range headers {
+				req.Header.Del(h)
+			}
+		}
+		return nil
+	})
+}
+
+// SetRedirectPolicy replaces the client's redirect handling. Policies run in
+// order and the first error wins. Like net/http, requests still fail after 10
+// redirects unless MaxRedirects is one of the policies.
+func (c *Client) SetRedirectPolicy(policies ...RedirectPolicy) *Client {
+	limited := false
+	for _, policy := range policies {
+		if _, ok := policy.(maxRedirects); ok {
+			limited = true
+		}
+	}
+
+	c.httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		for _, policy := range policies {
+			if err := policy.CheckRedirect(req, via); err != nil {
+				return err
+			}
+		}
+		if !limited && len(via) >= defaultMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", defaultMaxRedirects)
+		}
+		return nil
+	}
+	return c
+}
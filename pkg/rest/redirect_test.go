@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Client_SetRedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("other key=" + r.Header.Get("X-Api-Key")))
+	}))
+	defer other.Close()
+
+	var loops atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hop":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/loop":
+			loops.Add(1)
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, other.URL+"/x", http.StatusFound)
+		default:
+			w.Write([]byte("final"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithHeaders(map[string]string{"X-Api-Key": "secret"}))
+	resp, err := client.Get("/hop").Do()
+	if err != nil || resp.Text() != "final" || resp.FinalURL.Path != "/final" {
+		t.Fatalf("expected redirect to be followed, got %v, %v", resp, err)
+	}
+
+	client.SetRedirectPolicy(NoRedirects())
+	resp, err = client.Get("/hop").Do()
+	if err != nil || resp.StatusCode != http.StatusFound || resp.Headers.Get("Location") != "/final" {
+		t.Errorf("expected redirect response, got %v, %v", resp, err)
+	}
+
+	client.SetRedirectPolicy(RedirectPolicyFunc(func(req *http.Request, _ []*http.Request) error {
+		if req.URL.Path == "/final" {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}))
+	resp, err = client.Get("/hop").Do()
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Errorf("expected custom policy to stop, got %v, %v", resp, err)
+	}
+
+	client.SetRedirectPolicy(MaxRedirects(2))
+	if _, err := client.Get("/loop").SetRetries(0).Do(); err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Errorf("expected max redirects error, got %v", err)
+	}
+
+	client.SetRedirectPolicy(SameHostOnly())
+	resp, err = client.Get("/away").Do()
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Errorf("expected cross-host redirect to stop, got %v, %v", resp, err)
+	}
+
+	client.SetRedirectPolicy(StripHeaders("X-Api-Key"))
+	resp, err = client.Get("/away").Do()
+	if err != nil || resp.Text() != "other key=" {
+		t.Errorf("expected api key to be stripped, got %v, %v", resp, err)
+	}
+
+	// policies that do not limit redirects keep the default limit of 10
+	loops.Store(0)
+	if _, err := client.Get("/loop").SetRetries(0).Do(); err == nil || !strings.Contains(err.Error(), "stopped after 10 redirects") {
+		t.Errorf("expected default redirect limit, got %v", err)
+	}
+	if got := loops.Load(); got != 10 {
+		t.Errorf("expected 10 requests, got %d", got)
+	}
+
+	client.SetRedirectPolicy(StripHeaders("X-Api-Key"), MaxRedirects(15))
+	loops.Store(0)
+	if _, err := client.Get("/loop").SetRetries(0).Do(); err == nil || !strings.Contains(err.Error(), "stopped after 15 redirects") {
+		t.Errorf("expected MaxRedirects to replace the default limit, got %v", err)
+	}
+	if got := loops.Load(); got != 16 {
+		t.Errorf("expected 16 requests, got %d", got)
+	}
+}
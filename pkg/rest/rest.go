@@ -4,22 +4,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	defaultTimeout = 30 * time.Second
 	maxRetries     = 3
 	retryDelay     = 500 * time.Millisecond
+	maxRetryDelay  = 10 * time.Second
 )
 
 const (
@@ -28,29 +31,49 @@ const (
 	ContentTypeMultipart = "multipart/form-data"
 	ContentTypeXML       = "application/xml"
 	ContentTypeText      = "text/plain"
+	ContentTypeYAML      = "application/yaml"
+	ContentTypeBinary    = "application/octet-stream"
+
+	// bodyTypeReader marks a body set with SetBodyReader.
+	bodyTypeReader = "reader"
 )
 
 var defaultClient = NewClient()
 
+// Client holds the settings shared by its requests: base URL, default headers,
+// retry policy and a single http.Client, so connections are pooled across requests.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	headers    map[string]string
+	baseURL     string
+	httpClient  *http.Client
+	headers     map[string]string
+	retryPolicy RetryPolicy
+	middlewares []Middleware
+	cache       Middleware // innermost, see WithCache
+	breakers    *breakerGroup
+	limiter     atomic.Pointer[rateLimiter]
+	proxy       func(*url.URL) (*url.URL, error)
+	errorStatus bool
+	gzip        bool
+	maxBody     int64
+	hooks       hooks
 }
 
 type ClientOption func(*Client)
 
 func NewClient(opts ...ClientOption) *Client {
 	client := &Client{
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 20,
-				IdleConnTimeout:     90 * time.Second,
-			},
+		headers:     map[string]string{"User-Agent": DefaultUserAgent()},
+		retryPolicy: DefaultRetryPolicy(),
+		proxy:       environmentProxy,
+	}
+	client.httpClient = &http.Client{
+		Timeout: defaultTimeout,
+		Transport: &http.Transport{
+			Proxy:               client.proxyFor,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 20,
+			IdleConnTimeout:     90 * time.Second,
 		},
-		headers: make(map[string]string),
 	}
 
 	for _, opt := range opts {
@@ -66,17 +89,40 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithTransport replaces the client's transport, e.g. to share one
+// http.Transport between several clients.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithHTTPClient uses hc for all requests of the client.
+// A zero hc.Timeout disables the per-attempt timeout.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
 		c.baseURL = strings.TrimRight(baseURL, "/")
 	}
 }
 
+// WithRetryPolicy sets the default retry policy for requests built by the client.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithHeaders sets default headers sent with every request of the client,
+// see Client.SetDefaultHeaders.
 func WithHeaders(headers map[string]string) ClientOption {
 	return func(c *Client) {
-		for k, v := range headers {
-			c.headers[k] = v
-		}
+		c.SetDefaultHeaders(headers)
 	}
 }
 
@@ -90,9 +136,23 @@ type RequestBuilder struct {
 	pathParams  map[string]string
 	body        interface{}
 	bodyType    string
+	bodyReader  *readerBody
 	formData    url.Values
-	retries     int
-	files       map[string]string
+	parts       []multipartPart
+	retryPolicy RetryPolicy
+	// attemptTimeout bounds each attempt, overallTimeout the whole call
+	attemptTimeout time.Duration
+	overallTimeout time.Duration
+	errorStatus    bool
+	errorResult    interface{}
+	gzip           bool
+	maxBody        int64
+	hooks          hooks
+	proxy          *string
+	cookies        []*http.Cookie
+	progress       ProgressFunc
+	err            error
+	files          map[string]string
 }
 
 func (c *Client) newRequestBuilder(method, path string) *RequestBuilder {
@@ -105,7 +165,11 @@ func (c *Client) newRequestBuilder(method, path string) *RequestBuilder {
 		pathParams:  make(map[string]string),
 		formData:    make(url.Values),
 		files:       make(map[string]string),
-		retries:     maxRetries,
+		retryPolicy: c.retryPolicy,
+		errorStatus: c.errorStatus,
+		gzip:        c.gzip,
+		maxBody:     c.maxBody,
+		hooks:       c.hooks.clone(),
 	}
 }
 
@@ -113,9 +177,17 @@ func (c *Client) buildURL(path string) string {
 	if c.baseURL == "" {
 		return path
 	}
+	// only an absolute URL replaces the base URL, not a URL in the query
+	if u, err := url.Parse(path); err == nil && u.IsAbs() && u.Host != "" {
+		return path
+	}
 	return fmt.Sprintf("%s/%s", c.baseURL, strings.TrimLeft(path, "/"))
 }
 
+// R creates a request with an arbitrary method, path is joined to the base URL
+// unless it is an absolute URL.
+func (c *Client) R(method, path string) *RequestBuilder { return c.newRequestBuilder(method, path) }
+
 func (c *Client) Get(url string) *RequestBuilder     { return c.newRequestBuilder("GET", url) }
 func (c *Client) Post(url string) *RequestBuilder    { return c.newRequestBuilder("POST", url) }
 func (c *Client) Put(url string) *RequestBuilder     { return c.newRequestBuilder("PUT", url) }
@@ -123,6 +195,7 @@ func (c *Client) Delete(url string) *RequestBuilder  { return c.newRequestBuilde
 func (c *Client) Patch(url string) *RequestBuilder   { return c.newRequestBuilder("PATCH", url) }
 func (c *Client) Head(url string) *RequestBuilder    { return c.newRequestBuilder("HEAD", url) }
 func (c *Client) Options(url string) *RequestBuilder { return c.newRequestBuilder("OPTIONS", url) }
+func (c *Client) Trace(url string) *RequestBuilder   { return c.newRequestBuilder("TRACE", url) }
 
 func (rb *RequestBuilder) AddHeader(key, value string) *RequestBuilder {
 	rb.headers[key] = value
@@ -134,6 +207,8 @@ func (rb *RequestBuilder) AddQueryParam(key, value string) *RequestBuilder {
 	return rb
 }
 
+// AddPathParam sets the value of a {key} (or :key) placeholder in the URL path,
+// the value is path-escaped.
 func (rb *RequestBuilder) AddPathParam(key, value string) *RequestBuilder {
 	rb.pathParams[key] = value
 	return rb
@@ -145,11 +220,29 @@ func (rb *RequestBuilder) SetJSONBody(body interface{}) *RequestBuilder {
 	return rb
 }
 
+// SetXMLBody sets v encoded with encoding/xml as the request body.
+func (rb *RequestBuilder) SetXMLBody(v interface{}) *RequestBuilder {
+	rb.body = v
+	rb.bodyType = ContentTypeXML
+	return rb
+}
+
+// SetYAMLBody sets v encoded as YAML as the request body.
+func (rb *RequestBuilder) SetYAMLBody(v interface{}) *RequestBuilder {
+	rb.body = v
+	rb.bodyType = ContentTypeYAML
+	return rb
+}
+
+// SetFormData adds form fields, they are sent in the multipart body when the
+// request also has files.
 func (rb *RequestBuilder) SetFormData(data map[string]string) *RequestBuilder {
 	for k, v := range data {
 		rb.formData.Add(k, v)
 	}
-	rb.bodyType = ContentTypeForm
+	if rb.bodyType != ContentTypeMultipart {
+		rb.bodyType = ContentTypeForm
+	}
 	return rb
 }
 
@@ -159,21 +252,55 @@ func (rb *RequestBuilder) AddFile(fileName, filePath string) *RequestBuilder {
 	return rb
 }
 
+// SetRetries sets the number of retries after the first attempt.
 func (rb *RequestBuilder) SetRetries(retries int) *RequestBuilder {
-	rb.retries = retries
+	rb.retryPolicy.MaxAttempts = retries + 1
+	return rb
+}
+
+// SetAttemptTimeout bounds each attempt, including reading the body. The
+// client timeout (WithTimeout) still applies, so it can only be shortened.
+func (rb *RequestBuilder) SetAttemptTimeout(timeout time.Duration) *RequestBuilder {
+	rb.attemptTimeout = timeout
+	return rb
+}
+
+// SetOverallDeadline bounds the whole call: all attempts, the waits between
+// them and reading the body. No retry is started when its backoff would
+// outlast the deadline.
+func (rb *RequestBuilder) SetOverallDeadline(timeout time.Duration) *RequestBuilder {
+	rb.overallTimeout = timeout
+	return rb
+}
+
+// SetRetryPolicy overrides the client's retry policy for this request.
+func (rb *RequestBuilder) SetRetryPolicy(policy RetryPolicy) *RequestBuilder {
+	rb.retryPolicy = policy
+	return rb
+}
+
+// RetryOnStatus retries responses with the given status codes, e.g. RetryableStatus.
+func (rb *RequestBuilder) RetryOnStatus(codes ...int) *RequestBuilder {
+	rb.retryPolicy.RetryStatus = append(slices.Clone(rb.retryPolicy.RetryStatus), codes...)
+	return rb
+}
+
+// RetryIf replaces the default retry decision for this request.
+func (rb *RequestBuilder) RetryIf(fn func(*http.Response, error) bool) *RequestBuilder {
+	rb.retryPolicy.RetryIf = fn
 	return rb
 }
 
 func (rb *RequestBuilder) buildRequest() (*http.Request, error) {
+	if rb.err != nil {
+		return nil, rb.err
+	}
 	finalURL := rb.url
 
 	// process path params
-	for k, v := range rb.pathParams {
-		param := ":" + k
-		if !strings.Contains(finalURL, param) {
-			return nil, fmt.Errorf("path parameter %s not found in url %s", k, finalURL)
-		}
-		finalURL = strings.ReplaceAll(finalURL, param, url.PathEscape(v))
+	finalURL, err := expandPath(finalURL, rb.pathParams)
+	if err != nil {
+		return nil, err
 	}
 
 	// add query params
@@ -195,54 +322,68 @@ func (rb *RequestBuilder) buildRequest() (*http.Request, error) {
 			body = bytes.NewBuffer(jsonData)
 			contentType = ContentTypeJSON
 		}
+	case ContentTypeXML:
+		if rb.body != nil {
+			xmlData, err := xml.Marshal(rb.body)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(xmlData)
+			contentType = ContentTypeXML
+		}
+	case ContentTypeYAML:
+		if rb.body != nil {
+			yamlData, err := yaml.Marshal(rb.body)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(yamlData)
+			contentType = ContentTypeYAML
+		}
+	case bodyTypeReader:
+		r, err := rb.bodyReader.open()
+		if err != nil {
+			return nil, err
+		}
+		body = r
+		contentType = rb.bodyReader.contentType
 	case ContentTypeForm:
 		if len(rb.formData) > 0 {
 			body = strings.NewReader(rb.formData.Encode())
 			contentType = ContentTypeForm
 		}
 	case ContentTypeMultipart:
-		if len(rb.files) > 0 || len(rb.formData) > 0 {
-			var buf bytes.Buffer
-			writer := multipart.NewWriter(&buf)
-
-			for k, values := range rb.formData {
-				for _, v := range values {
-					if err := writer.WriteField(k, v); err != nil {
-						return nil, err
-					}
-				}
-			}
-			for field, filePath := range rb.files {
-				file, err := os.Open(filePath)
-				if err != nil {
-					return nil, err
-				}
-				defer file.Close()
-
-				part, err := writer.CreateFormFile(field, filepath.Base(filePath))
-				if err != nil {
-					return nil, err
-				}
-
-				if _, err := io.Copy(part, file); err != nil {
-					return nil, err
-				}
-			}
-			if err := writer.Close(); err != nil {
+		if len(rb.files) > 0 || len(rb.formData) > 0 || len(rb.parts) > 0 {
+			buf, ct, err := rb.buildMultipart()
+			if err != nil {
 				return nil, err
 			}
-
-			body = &buf
-			contentType = writer.FormDataContentType()
+			body = buf
+			contentType = ct
 		}
 	}
 
+	if body != nil && (rb.method == http.MethodHead || rb.method == http.MethodTrace) {
+		return nil, fmt.Errorf("%s request must not have a body", rb.method)
+	}
+
 	req, err := http.NewRequest(rb.method, finalURL, body)
 	if err != nil {
 		return nil, err
 	}
 
-	mergeHeaders(req, rb.headers, rb.client.headers)
+	if rb.bodyType == bodyTypeReader {
+		rb.bodyReader.prepare(req)
+	}
+	if rb.gzip {
+		gzipRequest(req)
+	}
+
+	// request headers override the client's default headers
+	mergeHeaders(req, rb.client.headers, rb.headers)
+	for _, cookie := range rb.cookies {
+		req.AddCookie(cookie)
+	}
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
@@ -250,48 +391,150 @@ func (rb *RequestBuilder) buildRequest() (*http.Request, error) {
 	return req, nil
 }
 
+// Do sends the request with context.Background(), see DoContext.
 func (rb *RequestBuilder) Do() (*Response, error) {
+	return rb.DoContext(context.Background())
+}
+
+// DoContext sends the request, retrying on transient errors.
+// ctx bounds the whole call: cancellation or deadline stops both the
+// in-flight attempt and any further retries.
+func (rb *RequestBuilder) DoContext(ctx context.Context) (*Response, error) {
+	return rb.finish(rb.do(ctx))
+}
+
+func (rb *RequestBuilder) do(ctx context.Context) (*Response, error) {
+	resp, err := rb.send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return rb.readResponse(resp)
+}
+
+// send runs the attempts of the request and returns the response with its
+// body unread, the caller must close it.
+func (rb *RequestBuilder) send(ctx context.Context) (*http.Response, error) {
+	if rb.overallTimeout <= 0 {
+		return rb.sendAttempts(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rb.overallTimeout)
+	resp, err := rb.sendAttempts(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the deadline also covers reading the body
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (rb *RequestBuilder) sendAttempts(ctx context.Context) (*http.Response, error) {
 	var resp *http.Response
 	var err error
+	var errs []error
 
-	for attempt := 0; attempt <= rb.retries; attempt++ {
-		req, _ := rb.buildRequest()
+	ctx, err = rb.proxyContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		ctx, cancel := context.WithTimeout(context.Background(), rb.client.httpClient.Timeout)
+	attempts := rb.retryPolicy.attempts()
+	if !rb.replayable() {
+		attempts = 1
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
+		req, buildErr := rb.buildRequest()
+		if buildErr != nil {
+			return nil, fmt.Errorf("failed to build request: %w", buildErr)
+		}
+		if attempt > 0 {
+			rb.hooks.retry(attempt+1, req, resp, err)
+		}
 
-		req = req.WithContext(ctx)
+		attemptCtx, cancel := rb.attemptContext(ctx)
+		resp, err = rb.client.roundTrip(req.WithContext(withAttempt(attemptCtx, attempt+1)))
+		retry := attempt < attempts-1 && ctx.Err() == nil && rb.retryPolicy.retry(resp, err)
+		if err == nil && !retry {
+			// keep the attempt context alive until the body is read
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			break
+		}
 
-		resp, err = rb.client.httpClient.Do(req)
+		wait := rb.retryPolicy.delay(attempt)
+		if err == nil {
+			if ra, ok := retryAfter(resp, time.Now()); ok {
+				wait = rb.retryPolicy.capDelay(ra)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			errs = append(errs, fmt.Errorf("attempt %d: %w", attempt+1, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}))
+		} else {
+			errs = append(errs, fmt.Errorf("attempt %d: %w", attempt+1, err))
+		}
 		cancel()
-
-		if shouldRetry(err) && attempt < rb.retries {
-			time.Sleep(retryDelay * time.Duration(1<<attempt))
-			continue
+		if !retry {
+			break
 		}
-		break
-	}
 
-	if err != nil {
-		return nil, fmt.Errorf("request failed after %d attempts: %w", rb.retries, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			errs = append(errs, context.DeadlineExceeded)
+			return nil, fmt.Errorf("request deadline exceeded after %d attempts: %w", attempt+1, errors.Join(errs...))
+		}
+		select {
+		case <-ctx.Done():
+			errs = append(errs, ctx.Err())
+			return nil, fmt.Errorf("request canceled after %d attempts: %w", attempt+1, errors.Join(errs...))
+		case <-time.After(wait):
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("request failed after %d attempts: %w", len(errs), errors.Join(errs...))
 	}
+	return resp, nil
+}
 
-	return &Response{
+func newResponse(resp *http.Response, body []byte) *Response {
+	response := &Response{
 		StatusCode: resp.StatusCode,
 		Headers:    resp.Header.Clone(),
 		body:       body,
-	}, nil
+	}
+	if resp.Request != nil {
+		response.FinalURL = resp.Request.URL
+	}
+	return response
+}
+
+func (rb *RequestBuilder) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := rb.client.httpClient.Timeout
+	if rb.attemptTimeout > 0 && (timeout <= 0 || rb.attemptTimeout < timeout) {
+		timeout = rb.attemptTimeout
+	}
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// cancelBody releases the per-attempt context once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
 
 func mergeHeaders(req *http.Request, headers ...map[string]string) {
 	for _, header := range headers {
 		for k, v := range header {
-			if strings.EqualFold(k, "host") && req.Host == "" {
+			if strings.EqualFold(k, "host") {
 				req.Host = v
 				continue
 			}
@@ -322,13 +565,25 @@ func shouldRetry(err error) bool {
 type Response struct {
 	StatusCode int
 	Headers    http.Header
-	body       []byte
+	// FinalURL is the URL of the last request, after following redirects.
+	FinalURL *url.URL
+	body     []byte
 }
 
 func (r *Response) JSON(v interface{}) error {
 	return json.Unmarshal(r.body, v)
 }
 
+// XML decodes the body with encoding/xml.
+func (r *Response) XML(v interface{}) error {
+	return xml.Unmarshal(r.body, v)
+}
+
+// YAML decodes the body as YAML.
+func (r *Response) YAML(v interface{}) error {
+	return yaml.Unmarshal(r.body, v)
+}
+
 func (r *Response) Text() string {
 	return string(r.body)
 }
@@ -346,71 +601,3 @@ func (r *Response) NoContent() bool {
 func SetBaseURL(baseURL string) {
 	defaultClient.baseURL = baseURL
 }
-
-type RequestOptions func(*RequestBuilder)
-
-func WithPathParams(params map[string]string) RequestOptions {
-	return func(rb *RequestBuilder) {
-		for k, v := range params {
-			rb.AddPathParam(k, v)
-		}
-	}
-}
-
-func WithQueryParams(params map[string]string) RequestOptions {
-	return func(rb *RequestBuilder) {
-		for k, v := range params {
-			rb.AddQueryParam(k, v)
-		}
-	}
-}
-
-func WithRequestHeaders(headers map[string]string) RequestOptions {
-	return func(rb *RequestBuilder) {
-		for k, v := range headers {
-			rb.AddHeader(k, v)
-		}
-	}
-}
-func WithJSONBody(body interface{}) RequestOptions {
-	return func(rb *RequestBuilder) {
-		rb.SetJSONBody(body)
-	}
-}
-
-func WithFormData(data map[string]string) RequestOptions {
-	return func(rb *RequestBuilder) {
-		rb.SetFormData(data)
-	}
-}
-
-func WithFile(fileName, filePath string) RequestOptions {
-	return func(rb *RequestBuilder) {
-		rb.AddFile(fileName, filePath)
-	}
-}
-
-func Get(path string, opts ...RequestOptions) (*Response, error) {
-	return doRequest(defaultClient.Get(path), opts...)
-}
-
-func Post(path string, opts ...RequestOptions) (*Response, error) {
-	return doRequest(defaultClient.Post(path), opts...)
-}
-func Put(path string, opts ...RequestOptions) (*Response, error) {
-	return doRequest(defaultClient.Put(path), opts...)
-}
-
-func Delete(path string, opts ...RequestOptions) (*Response, error) {
-	return doRequest(defaultClient.Delete(path), opts...)
-}
-func Patch(path string, opts ...RequestOptions) (*Response, error) {
-	return doRequest(defaultClient.Patch(path), opts...)
-}
-func doRequest(rb *RequestBuilder, opts ...RequestOptions) (*Response, error) {
-	for _, opt := range opts {
-		opt(rb)
-	}
-
-	return rb.Do()
-}
@@ -1,10 +1,20 @@
 package rest
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 func Test_GetWithBaseURL(t *testing.T) {
 	SetBaseURL("http://localhost:8080")
-	resp, _ := Get("/ping/:id",
+	resp, _ := Get(context.Background(), "/ping/:id",
 		WithPathParams(map[string]string{"id": "123"}),
 		WithQueryParams(map[string]string{"details": "true"}),
 		WithRequestHeaders(map[string]string{"Authorization": "Bearer token"}),
@@ -13,7 +23,7 @@ func Test_GetWithBaseURL(t *testing.T) {
 }
 
 func Test_Get(t *testing.T) {
-	resp, _ := Get("http://localhost:8080/ping/")
+	resp, _ := Get(context.Background(), "http://localhost:8080/ping/")
 	t.Log(resp.Text())
 }
 
@@ -24,7 +34,7 @@ type User struct {
 
 func Test_Post(t *testing.T) {
 	SetBaseURL("http://localhost:8080")
-	resp, _ := Post("/user",
+	resp, _ := Post(context.Background(), "/user",
 		WithJSONBody(User{Name: "John", Email: "john@example.com"}),
 		WithRequestHeaders(map[string]string{
 			"X-Request-ID": "ncahdlai",
@@ -36,3 +46,169 @@ func Test_Post(t *testing.T) {
 	t.Log(err)
 	t.Log(user.Name)
 }
+
+func Test_DoContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte("late"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewClient().Get(server.URL).DoContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request should stop with the parent context, took %v", elapsed)
+	}
+}
+
+func Test_ClientReuse(t *testing.T) {
+	var remotes sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remotes.Store(r.RemoteAddr, true)
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + r.Header.Get("X-Tenant")))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL+"/api/"),
+		WithHeaders(map[string]string{"X-Tenant": "t1"}),
+		WithHTTPClient(&http.Client{Transport: &http.Transport{}}),
+	)
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.R("report", "/users").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Text(); got != "REPORT /api/users t1" {
+			t.Errorf("unexpected response %q", got)
+		}
+	}
+
+	count := 0
+	remotes.Range(func(_, _ any) bool { count++; return true })
+	if count != 1 {
+		t.Errorf("expected one pooled connection, got %d", count)
+	}
+
+	resp, err := client.Get(server.URL + "/absolute").Do()
+	if err != nil || resp.Text() != "GET /absolute t1" {
+		t.Errorf("absolute URL should bypass base URL, got %v, %v", resp, err)
+	}
+}
+
+func Test_XMLAndYAML(t *testing.T) {
+	type item struct {
+		Name  string `xml:"name" yaml:"name"`
+		Count int    `xml:"count" yaml:"count"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// echo the body back with its content type
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	in := item{Name: "demo", Count: 2}
+
+	resp, err := client.Post("/xml").SetXMLBody(in).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out item
+	if err := resp.XML(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in || resp.Headers.Get("Content-Type") != ContentTypeXML {
+		t.Errorf("xml round trip = %+v (%s)", out, resp.Headers.Get("Content-Type"))
+	}
+
+	resp, err = client.Post("/yaml").SetYAMLBody(in).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = item{}
+	if err := resp.YAML(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in || resp.Headers.Get("Content-Type") != ContentTypeYAML {
+		t.Errorf("yaml round trip = %+v (%s)", out, resp.Headers.Get("Content-Type"))
+	}
+}
+
+func Test_Timeouts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow-once":
+			if calls.Add(1) == 1 {
+				select {
+				case <-time.After(time.Second):
+				case <-r.Context().Done():
+				}
+			}
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   40 * time.Millisecond,
+		Backoff:     ConstantBackoff,
+		RetryStatus: []int{http.StatusServiceUnavailable},
+	}))
+
+	t.Run("attempt timeout", func(t *testing.T) {
+		start := time.Now()
+		resp, err := client.Get("/slow-once").SetAttemptTimeout(50 * time.Millisecond).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.OK() || calls.Load() != 2 {
+			t.Errorf("status = %d after %d calls", resp.StatusCode, calls.Load())
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("took %v, the slow attempt was not cut short", elapsed)
+		}
+	})
+
+	t.Run("overall deadline", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.Get("/unavailable").SetOverallDeadline(100 * time.Millisecond).Do()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want deadline exceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("took %v, beyond the 100ms budget", elapsed)
+		}
+	})
+}
+
+func Test_BuildURL(t *testing.T) {
+	client := NewClient(WithBaseURL("https://api.example.com/v1"))
+	tests := []struct {
+		path, want string
+	}{
+		{"/users", "https://api.example.com/v1/users"},
+		{"/login?next=https://example.com", "https://api.example.com/v1/login?next=https://example.com"},
+		{"https://other.example.com/x", "https://other.example.com/x"},
+	}
+	for _, tt := range tests {
+		if got := client.buildURL(tt.path); got != tt.want {
+			t.Errorf("buildURL(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
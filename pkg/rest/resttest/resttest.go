@@ -0,0 +1,209 @@
+// Package resttest provides transports for testing code built on rest without
+// a live server: Mock answers requests from stubs, Recorder records real
+// exchanges to a fixture file and replays them.
+//
+//	mock := resttest.NewMock()
+//	mock.On("GET", "/users/1").ReplyJSON(200, user)
+//	client := rest.NewClient(rest.WithBaseURL("http://api"), rest.WithTransport(mock))
+//	...
+//	mock.AssertExpectations(t)
+package resttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Matcher reports whether a request matches a stub.
+type Matcher func(*http.Request) bool
+
+// Query matches requests with the query param key set to value.
+func Query(key, value string) Matcher {
+	return func(req *http.Request) bool {
+		return req.URL.Query().Get(key) == value
+	}
+}
+
+// Header matches requests with the header key set to value.
+func Header(key, value string) Matcher {
+	return func(req *http.Request) bool {
+		return req.Header.Get(key) == value
+	}
+}
+
+// BodyContains matches requests whose body contains s.
+func BodyContains(s string) Matcher {
+	return func(req *http.Request) bool {
+		return strings.Contains(string(readBody(req)), s)
+	}
+}
+
+// Stub is the canned response for requests matching a method, path and matchers.
+type Stub struct {
+	method   string
+	path     string
+	matchers []Matcher
+	status   int
+	header   http.Header
+	body     []byte
+	err      error
+	times    int
+	calls    int
+}
+
+// Match adds matchers the request must satisfy.
+func (s *Stub) Match(matchers ...Matcher) *Stub {
+	s.matchers = append(s.matchers, matchers...)
+	return s
+}
+
+// Reply sets the response status and body.
+func (s *Stub) Reply(status int, body string) *Stub {
+	s.status = status
+	s.body = []byte(body)
+	return s
+}
+
+// ReplyJSON sets the response status and v encoded as the JSON body.
+func (s *Stub) ReplyJSON(status int, v interface{}) *Stub {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("resttest: encode reply: %v", err))
+	}
+	s.status = status
+	s.body = body
+	s.header.Set("Content-Type", "application/json")
+	return s
+}
+
+// ReplyError makes matching requests fail with err instead of a response.
+func (s *Stub) ReplyError(err error) *Stub {
+	s.err = err
+	return s
+}
+
+// SetHeader sets a response header.
+func (s *Stub) SetHeader(key, value string) *Stub {
+	s.header.Set(key, value)
+	return s
+}
+
+// Times expects the stub to be called exactly n times, see AssertExpectations.
+// After n calls the stub no longer matches.
+func (s *Stub) Times(n int) *Stub {
+	s.times = n
+	return s
+}
+
+// Calls returns how many requests the stub answered.
+func (s *Stub) Calls() int {
+	return s.calls
+}
+
+func (s *Stub) matches(req *http.Request) bool {
+	if s.method != req.Method || s.path != req.URL.Path {
+		return false
+	}
+	if s.times > 0 && s.calls >= s.times {
+		return false
+	}
+	for _, m := range s.matchers {
+		if !m(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// Mock is an http.RoundTripper answering requests from stubs, checked in the
+// order they were added.
+type Mock struct {
+	mu    sync.Mutex
+	stubs []*Stub
+}
+
+// NewMock creates a Mock without stubs, unmatched requests fail.
+func NewMock() *Mock {
+	return &Mock{}
+}
+
+// On adds a stub for method and URL path, answering 200 with an empty body
+// until configured otherwise.
+func (m *Mock) On(method, path string) *Stub {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &Stub{method: strings.ToUpper(method), path: path, status: http.StatusOK, header: make(http.Header)}
+	m.stubs = append(m.stubs, s)
+	return s
+}
+
+// RoundTrip implements http.RoundTripper.
+func (m *Mock) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.stubs {
+		if !s.matches(req) {
+			continue
+		}
+		s.calls++
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		if s.err != nil {
+			return nil, s.err
+		}
+		return newResponse(req, s.status, s.header, s.body), nil
+	}
+	return nil, fmt.Errorf("resttest: no stub for %s %s", req.Method, req.URL)
+}
+
+// AssertExpectations fails t for stubs that were never called, or called a
+// different number of times than set with Times.
+func (m *Mock) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.stubs {
+		switch {
+		case s.times > 0 && s.calls != s.times:
+			t.Errorf("resttest: %s %s called %d times, want %d", s.method, s.path, s.calls, s.times)
+		case s.times == 0 && s.calls == 0:
+			t.Errorf("resttest: %s %s never called", s.method, s.path)
+		}
+	}
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readBody reads the request body and puts it back for the next reader.
+func readBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, _ := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body
+}
@@ -0,0 +1,106 @@
+package resttest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chhz0/go-component-base/pkg/rest"
+)
+
+func Test_Mock(t *testing.T) {
+	mock := NewMock()
+	users := mock.On("GET", "/users").Match(Query("page", "2")).ReplyJSON(http.StatusOK, []string{"ann"}).Times(2)
+	create := mock.On("POST", "/users").Match(BodyContains(`"bob"`)).Reply(http.StatusCreated, "created")
+	mock.On("GET", "/down").ReplyError(errors.New("connection refused"))
+
+	client := rest.NewClient(rest.WithBaseURL("http://api.test"), rest.WithTransport(mock), rest.WithRetryPolicy(rest.RetryPolicy{}))
+
+	for i := 0; i < 2; i++ {
+		var names []string
+		resp, err := client.Get("/users").AddQueryParam("page", "2").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := resp.JSON(&names); err != nil || len(names) != 1 {
+			t.Errorf("names = %v, err = %v", names, err)
+		}
+	}
+	if _, err := client.Get("/users").AddQueryParam("page", "2").Do(); err == nil {
+		t.Error("stub answered beyond Times(2)")
+	}
+
+	resp, err := client.Post("/users").SetJSONBody(map[string]string{"name": "bob"}).Do()
+	if err != nil || !resp.Created() {
+		t.Fatalf("create = %v, %v", resp, err)
+	}
+	if _, err := client.Get("/down").Do(); err == nil {
+		t.Error("ReplyError not returned")
+	}
+
+	if users.Calls() != 2 || create.Calls() != 1 {
+		t.Errorf("calls = %d, %d", users.Calls(), create.Calls())
+	}
+	mock.AssertExpectations(t)
+}
+
+func Test_Recorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen", r.URL.Path)
+		w.Header().Set("Set-Cookie", "session=s3cret")
+		w.Write([]byte("live " + r.URL.Path))
+	}))
+	fixture := filepath.Join(t.TempDir(), "fixtures", "api.json")
+	if ModeFor(fixture) != ModeRecord {
+		t.Fatal("missing fixture should record")
+	}
+
+	rec, err := NewRecorder(fixture, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := rest.NewClient(rest.WithBaseURL(server.URL), rest.WithTransport(rec))
+	for _, path := range []string{"/a", "/b", "/c?api_key=s3cret&page=2"} {
+		if _, err := client.Get(path).Do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("fixture leaks credentials:\n%s", data)
+	}
+
+	if ModeFor(fixture) != ModeReplay {
+		t.Fatal("existing fixture should replay")
+	}
+	rec, err = NewRecorder(fixture, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = rest.NewClient(rest.WithBaseURL(server.URL), rest.WithTransport(rec), rest.WithRetryPolicy(rest.RetryPolicy{}))
+	resp, err := client.Get("/b").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "live /b" || resp.Headers.Get("X-Seen") != "/b" {
+		t.Errorf("replayed %q %v", resp.Text(), resp.Headers)
+	}
+	if _, err := client.Get("/b").Do(); err == nil {
+		t.Error("interaction replayed twice")
+	}
+	resp, err = client.Get("/c?api_key=s3cret&page=2").Do()
+	if err != nil || resp.Text() != "live /c" {
+		t.Errorf("replay with masked query: %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package resttrace adds OpenTelemetry client spans to rest requests.
+//
+// Every attempt gets its own span carrying the method, URL, status code and
+// resend count, and the trace context is injected into the request headers:
+//
+//	client := rest.NewClient(resttrace.WithTracing())
+package resttrace
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chhz0/go-component-base/pkg/rest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/chhz0/go-component-base/pkg/rest/resttrace"
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	propagators    propagation.TextMapPropagator
+	spanName       func(*http.Request) string
+}
+
+type Option func(*options)
+
+// WithTracerProvider sets the provider used to create spans, otel.GetTracerProvider() by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithPropagators sets the propagators used to inject trace headers,
+// otel.GetTextMapPropagator() by default.
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagators = p
+	}
+}
+
+// WithSpanNameFormatter sets the span name, "HTTP <METHOD>" by default.
+func WithSpanNameFormatter(fn func(*http.Request) string) Option {
+	return func(o *options) {
+		o.spanName = fn
+	}
+}
+
+// WithTracing returns a rest.ClientOption installing Middleware.
+func WithTracing(opts ...Option) rest.ClientOption {
+	return rest.WithMiddleware(Middleware(opts...))
+}
+
+// Middleware creates a client span for each attempt and injects the trace context.
+func Middleware(opts ...Option) rest.Middleware {
+	o := &options{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+		spanName: func(req *http.Request) string {
+			return "HTTP " + req.Method
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	tracer := o.tracerProvider.Tracer(instrumentationName)
+
+	return func(next rest.RoundTripFunc) rest.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.full", req.URL.Redacted()),
+				attribute.String("server.address", req.URL.Hostname()),
+			}
+			if port := req.URL.Port(); port != "" {
+				if p, err := strconv.Atoi(port); err == nil {
+					attrs = append(attrs, attribute.Int("server.port", p))
+				}
+			}
+			if attempt := rest.Attempt(req.Context()); attempt > 1 {
+				attrs = append(attrs, attribute.Int("http.request.resend_count", attempt-1))
+			}
+
+			ctx, span := tracer.Start(req.Context(), o.spanName(req),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			req = req.WithContext(ctx)
+			o.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, resp.Status)
+			}
+			return resp, nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package resttrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chhz0/go-component-base/pkg/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_Middleware(t *testing.T) {
+	var hits atomic.Int32
+	var traceparent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("Traceparent"))
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := rest.NewClient(
+		rest.WithRetryPolicy(rest.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithTracing(WithTracerProvider(tp), WithPropagators(propagation.TraceContext{})),
+	)
+	resp, err := client.Get(server.URL).RetryOnStatus(rest.RetryableStatus...).Do()
+	if err != nil || resp.Text() != "ok" {
+		t.Fatalf("unexpected response %v, %v", resp, err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected one span per attempt, got %d", len(spans))
+	}
+	if spans[0].SpanKind() != trace.SpanKindClient || spans[0].Name() != "HTTP GET" {
+		t.Errorf("unexpected span %s (%v)", spans[0].Name(), spans[0].SpanKind())
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("expected error status for 503, got %v", spans[0].Status())
+	}
+	if !hasAttr(spans[1].Attributes(), attribute.Int("http.request.resend_count", 1)) ||
+		!hasAttr(spans[1].Attributes(), attribute.Int("http.response.status_code", 200)) {
+		t.Errorf("unexpected retry span attributes %v", spans[1].Attributes())
+	}
+
+	want := spans[1].SpanContext().TraceID().String()
+	if got, _ := traceparent.Load().(string); len(got) < 35 || got[3:35] != want {
+		t.Errorf("expected traceparent with trace id %s, got %q", want, got)
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
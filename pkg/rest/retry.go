@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryableStatus are the status codes usually worth retrying, for use with
+// RetryPolicy.RetryStatus or RequestBuilder.RetryOnStatus.
+var RetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// StatusError records a response status that triggered a retry.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %s", e.Status)
+}
+
+// Backoff returns the delay before retry number attempt (starting at 0).
+type Backoff func(base time.Duration, attempt int) time.Duration
+
+// ConstantBackoff waits base between every attempt.
+func ConstantBackoff(base time.Duration, _ int) time.Duration {
+	return base
+}
+
+// LinearBackoff waits base, 2*base, 3*base, ...
+func LinearBackoff(base time.Duration, attempt int) time.Duration {
+	return base * time.Duration(attempt+1)
+}
+
+// ExponentialBackoff waits base, 2*base, 4*base, ...
+func ExponentialBackoff(base time.Duration, attempt int) time.Duration {
+	if attempt > 30 {
+		attempt = 30
+	}
+	return base * time.Duration(1<<attempt)
+}
+
+// RetryPolicy controls how many times a request is attempted and how long to
+// wait between attempts.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one,
+	// values <= 1 disable retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay caps a single delay, 0 means no cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each delay by ±Jitter (0 to 1) of its value.
+	Jitter float64
+	// Backoff computes the delay from BaseDelay, defaults to ExponentialBackoff.
+	Backoff Backoff
+	// RetryStatus lists response status codes to retry, none by default.
+	// A Retry-After header on such responses replaces the backoff delay.
+	RetryStatus []int
+	// RetryIf replaces the default decision (transient transport errors and
+	// RetryStatus) when set. resp is nil when err is not.
+	RetryIf func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy retries up to 3 times with exponential backoff from 500ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: maxRetries + 1,
+		BaseDelay:   retryDelay,
+		MaxDelay:    maxRetryDelay,
+		Jitter:      0.1,
+		Backoff:     ExponentialBackoff,
+	}
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retry(resp *http.Response, err error) bool {
+	if p.RetryIf != nil {
+		return p.RetryIf(resp, err)
+	}
+	if err != nil {
+		return shouldRetry(err)
+	}
+	return slices.Contains(p.RetryStatus, resp.StatusCode)
+}
+
+func (p RetryPolicy) capDelay(d time.Duration) time.Duration {
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return d
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff
+	}
+
+	d := p.capDelay(backoff(p.BaseDelay, attempt))
+	if p.Jitter > 0 && d > 0 {
+		jitter := min(p.Jitter, 1)
+		d = time.Duration(float64(d) * (1 - jitter + 2*jitter*rand.Float64()))
+	}
+	return d
+}
+
+// retryAfter parses the Retry-After header as delay seconds or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
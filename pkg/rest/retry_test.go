@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_RetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for attempt, w := range want {
+		if got := p.delay(attempt); got != w*time.Millisecond {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, w*time.Millisecond, got)
+		}
+	}
+
+	p.Backoff = LinearBackoff
+	if got := p.delay(2); got != 300*time.Millisecond {
+		t.Errorf("linear: got %v", got)
+	}
+
+	p = RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5, Backoff: ConstantBackoff}
+	for i := 0; i < 100; i++ {
+		if got := p.delay(i); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("jitter out of range: %v", got)
+		}
+	}
+}
+
+func Test_RetryPolicy_Attempts(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithTimeout(20*time.Millisecond),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	)
+	_, err := client.Get(server.URL).DoContext(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected wrapped attempt errors, got %v", err)
+	}
+	for _, want := range []string{"after 3 attempts", "attempt 1:", "attempt 3:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func Test_RetryPolicy_Status(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}))
+
+	// status retries are opt-in
+	resp, err := client.Get(server.URL).Do()
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without retry, got %v, %v", resp, err)
+	}
+
+	hits.Store(0)
+	resp, err = client.Get(server.URL).RetryOnStatus(RetryableStatus...).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "ok" || hits.Load() != 3 {
+		t.Errorf("expected success on third attempt, got %q after %d", resp.Text(), hits.Load())
+	}
+
+	hits.Store(0)
+	calls := 0
+	resp, err = client.Get(server.URL).RetryIf(func(resp *http.Response, err error) bool {
+		calls++
+		return false
+	}).Do()
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || calls != 1 {
+		t.Errorf("expected RetryIf to stop retries, got %v, %v, %d calls", resp, err, calls)
+	}
+}
+
+func Test_RetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"3":                             3 * time.Second,
+		"Mon, 01 Jan 2024 00:00:10 GMT": 10 * time.Second,
+		"Sun, 31 Dec 2023 23:59:00 GMT": 0,
+	}
+	for header, want := range cases {
+		resp := &http.Response{Header: http.Header{"Retry-After": {header}}}
+		got, ok := retryAfter(resp, now)
+		if !ok || got != want {
+			t.Errorf("%q: expected %v, got %v (%v)", header, want, got, ok)
+		}
+	}
+
+	if _, ok := retryAfter(&http.Response{Header: http.Header{}}, now); ok {
+		t.Error("expected no Retry-After")
+	}
+}
@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSSERetry is the reconnect delay until the server sends a retry field.
+const defaultSSERetry = 3 * time.Second
+
+// Event is a server-sent event.
+type Event struct {
+	ID    string
+	Event string // "message" when the server sets no event type
+	Data  string // data lines joined with "\n"
+}
+
+// SSEHandler receives events, returning an error stops the stream.
+type SSEHandler func(Event) error
+
+// SSE connects to the event stream at path with Accept: text/event-stream and
+// passes each event to handler. When the connection drops it reconnects after
+// the server-provided retry delay (3s by default), sending the last seen
+// event ID as Last-Event-ID. It returns when ctx is done, when handler
+// returns an error, or when the server answers with a non-2xx status, or
+// with 204 No Content to end the stream (nil error).
+// The client timeout does not apply to the stream.
+func (c *Client) SSE(ctx context.Context, path string, handler SSEHandler) error {
+	s := &sseStream{client: c, path: path, handler: handler, retry: defaultSSERetry}
+	for {
+		err := s.connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var stop *sseStop
+		if errors.As(err, &stop) {
+			return stop.err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.retry):
+		}
+	}
+}
+
+// SSEChannel is SSE delivering events on a channel, closed when the stream
+// ends. The error, if any, is sent on the second channel.
+func (c *Client) SSEChannel(ctx context.Context, path string) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errc <- c.SSE(ctx, path, func(e Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return events, errc
+}
+
+// sseStop ends the stream instead of reconnecting, err is nil when the
+// server ended it with 204.
+type sseStop struct{ err error }
+
+func (e *sseStop) Error() string {
+	if e.err == nil {
+		return "event stream closed by server"
+	}
+	return e.err.Error()
+}
+
+type sseStream struct {
+	client      *Client
+	path        string
+	handler     SSEHandler
+	lastEventID string
+	retry       time.Duration
+}
+
+func (s *sseStream) connect(ctx context.Context) error {
+	rb := s.client.Get(s.path).
+		AddHeader("Accept", "text/event-stream").
+		AddHeader("Cache-Control", "no-cache").
+		SetRetryPolicy(RetryPolicy{})
+	if s.lastEventID != "" {
+		rb.AddHeader("Last-Event-ID", s.lastEventID)
+	}
+	req, err := rb.buildRequest()
+	if err != nil {
+		return &sseStop{err}
+	}
+
+	// the stream lives as long as ctx, not the client timeout
+	hc := *s.client.httpClient
+	hc.Timeout = 0
+	resp, err := s.client.chain(s.client.rateLimitMiddleware(hc.Do))(req.WithContext(withAttempt(ctx, 1)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return &sseStop{}
+	case !successful(resp.StatusCode):
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &sseStop{&HTTPError{StatusCode: resp.StatusCode, Headers: resp.Header, Body: body}}
+	case !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+		return &sseStop{fmt.Errorf("unexpected content type %q for event stream", resp.Header.Get("Content-Type"))}
+	}
+	return s.read(resp.Body)
+}
+
+// read parses the stream as in the HTML event stream format.
+func (s *sseStream) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var data []string
+	var event Event
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data != nil {
+				event.ID = s.lastEventID
+				event.Data = strings.Join(data, "\n")
+				if event.Event == "" {
+					event.Event = "message"
+				}
+				if err := s.handler(event); err != nil {
+					return &sseStop{err}
+				}
+			}
+			data, event = nil, Event{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event":
+			event.Event = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Client_SSE(t *testing.T) {
+	var connects atomic.Int32
+	var lastIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if connects.Add(1) == 1 {
+			// two events, then the connection drops
+			fmt.Fprint(w, "retry: 10\n: comment\n\nid: 1\ndata: first\n\n")
+			fmt.Fprint(w, "id: 2\nevent: update\ndata: line1\ndata: line2\n\n")
+			return
+		}
+		fmt.Fprint(w, "id: 3\ndata: {\"done\":true}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stop := errors.New("stop")
+	var events []Event
+	err := NewClient(WithBaseURL(server.URL)).SSE(ctx, "/events", func(e Event) error {
+		events = append(events, e)
+		if e.ID == "3" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want handler error", err)
+	}
+
+	want := []Event{
+		{ID: "1", Event: "message", Data: "first"},
+		{ID: "2", Event: "update", Data: "line1\nline2"},
+		{ID: "3", Event: "message", Data: `{"done":true}`},
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %q\nwant %q", events, want)
+	}
+	if fmt.Sprint(lastIDs) != "[ 2]" {
+		t.Errorf("Last-Event-ID per connection = %q, want reconnect from 2", lastIDs)
+	}
+}
+
+func Test_Client_SSEChannel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Last-Event-ID") != "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 1\nid: a\ndata: x\n\n")
+	}))
+	defer server.Close()
+
+	events, errc := NewClient(WithBaseURL(server.URL)).SSEChannel(context.Background(), "/events")
+	var got []string
+	for e := range events {
+		got = append(got, e.Data)
+	}
+	if err := <-errc; err != nil || fmt.Sprint(got) != "[x]" {
+		t.Errorf("events = %v, err = %v", got, err)
+	}
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// The TLS options below configure the client's *http.Transport and are
+// ignored for other transports. Pass them after WithTransport/WithHTTPClient.
+
+// WithTLSConfig replaces the TLS configuration of the client's transport.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+			t.TLSClientConfig = cfg
+		}
+	}
+}
+
+// WithRootCAs verifies servers against pool instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		if cfg := c.tlsConfig(); cfg != nil {
+			cfg.RootCAs = pool
+		}
+	}
+}
+
+// WithClientCertificates presents certs to servers requiring mutual TLS,
+// see tls.LoadX509KeyPair.
+func WithClientCertificates(certs ...tls.Certificate) ClientOption {
+	return func(c *Client) {
+		if cfg := c.tlsConfig(); cfg != nil {
+			cfg.Certificates = append(cfg.Certificates, certs...)
+		}
+	}
+}
+
+// WithMinTLSVersion sets the minimum TLS version, e.g. tls.VersionTLS13.
+func WithMinTLSVersion(version uint16) ClientOption {
+	return func(c *Client) {
+		if cfg := c.tlsConfig(); cfg != nil {
+			cfg.MinVersion = version
+		}
+	}
+}
+
+// WithInsecureSkipVerify disables server certificate verification,
+// for development only.
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *Client) {
+		if cfg := c.tlsConfig(); cfg != nil {
+			cfg.InsecureSkipVerify = true
+		}
+	}
+}
+
+// LoadCertPool builds a certificate pool from PEM encoded CA files.
+func LoadCertPool(pemFiles ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, file := range pemFiles {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", file)
+		}
+	}
+	return pool, nil
+}
+
+// tlsConfig returns the TLS config of the client's transport, creating it if
+// needed, or nil if the transport is not an *http.Transport.
+func (c *Client) tlsConfig() *tls.Config {
+	t, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig
+}
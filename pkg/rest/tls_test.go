@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Client_TLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.Organization[0]))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	cert := server.TLS.Certificates[0]
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := LoadCertPool(caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noRetry := WithRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	if _, err := NewClient(noRetry, WithClientCertificates(cert)).Get(server.URL).Do(); err == nil {
+		t.Error("expected unknown authority error")
+	}
+	if _, err := NewClient(noRetry, WithRootCAs(pool)).Get(server.URL).Do(); err == nil {
+		t.Error("expected error without client certificate")
+	}
+
+	client := NewClient(noRetry, WithRootCAs(pool), WithClientCertificates(cert), WithMinTLSVersion(tls.VersionTLS12))
+	resp, err := client.Get(server.URL).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "Acme Co" {
+		t.Errorf("unexpected peer certificate %q", resp.Text())
+	}
+
+	insecure := NewClient(noRetry, WithInsecureSkipVerify(), WithClientCertificates(cert))
+	if _, err := insecure.Get(server.URL).Do(); err != nil {
+		t.Errorf("expected insecure client to skip verification, got %v", err)
+	}
+
+	custom := NewClient(noRetry, WithTLSConfig(&tls.Config{RootCAs: x509.NewCertPool()}))
+	if _, err := custom.Get(server.URL).Do(); err == nil {
+		t.Error("expected custom TLS config to be used")
+	}
+}
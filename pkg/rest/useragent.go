@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const modulePath = "github.com/chhz0/go-component-base"
+
+// SetDefaultHeaders adds headers sent with every request of the client, e.g.
+// a tenant ID. Headers set on a request override them.
+// It must not be called concurrently with requests.
+func (c *Client) SetDefaultHeaders(headers map[string]string) *Client {
+	for k, v := range headers {
+		c.headers[http.CanonicalHeaderKey(k)] = v
+	}
+	return c
+}
+
+// UserAgent builds a User-Agent header of the form
+//
+//	myapp/1.2.0 (linux; amd64) go-component-base/v0.3.0 Go/go1.23.2
+//
+// naming the application first and the rest package last.
+type UserAgent struct {
+	Product string
+	Version string
+	// Comments are added in parentheses after the product, the OS and
+	// architecture by default.
+	Comments []string
+}
+
+func (u UserAgent) String() string {
+	var b strings.Builder
+	if u.Product != "" {
+		b.WriteString(u.Product)
+		if u.Version != "" {
+			b.WriteString("/" + u.Version)
+		}
+		comments := u.Comments
+		if comments == nil {
+			comments = []string{runtime.GOOS, runtime.GOARCH}
+		}
+		if len(comments) > 0 {
+			b.WriteString(" (" + strings.Join(comments, "; ") + ")")
+		}
+		b.WriteByte(' ')
+	}
+	b.WriteString(DefaultUserAgent())
+	return b.String()
+}
+
+// WithUserAgent sets the User-Agent of the client's requests to
+// UserAgent{Product: product, Version: version}.
+func WithUserAgent(product, version string) ClientOption {
+	return WithHeaders(map[string]string{
+		"User-Agent": UserAgent{Product: product, Version: version}.String(),
+	})
+}
+
+var defaultUserAgent = sync.OnceValue(func() string {
+	return "go-component-base/" + moduleVersion() + " Go/" + runtime.Version()
+})
+
+// DefaultUserAgent is the User-Agent sent unless the client or request sets
+// one, naming this module's version and the Go version.
+func DefaultUserAgent() string {
+	return defaultUserAgent()
+}
+
+// moduleVersion returns the version of this module in the running binary.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "devel"
+}
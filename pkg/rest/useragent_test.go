@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_Client_DefaultHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("User-Agent") + "|" + r.Header.Get("X-Tenant") + "|" + r.Header.Get("X-Region")))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithUserAgent("billing", "1.4.0")).
+		SetDefaultHeaders(map[string]string{"X-Tenant": "acme", "X-Region": "eu"})
+
+	resp, err := client.Get("/").AddHeader("x-region", "us").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(resp.Text(), "|")
+	if !strings.HasPrefix(parts[0], "billing/1.4.0 (") || !strings.Contains(parts[0], " go-component-base/") {
+		t.Errorf("User-Agent = %q", parts[0])
+	}
+	if parts[1] != "acme" || parts[2] != "us" {
+		t.Errorf("tenant = %q, region = %q, want the request header to win", parts[1], parts[2])
+	}
+
+	resp, err = NewClient(WithBaseURL(server.URL)).Get("/").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ua := strings.Split(resp.Text(), "|")[0]; ua != DefaultUserAgent() {
+		t.Errorf("default User-Agent = %q, want %q", ua, DefaultUserAgent())
+	}
+}
+
+func Test_UserAgent_String(t *testing.T) {
+	got := UserAgent{Product: "cli", Version: "2.0", Comments: []string{"ci"}}.String()
+	if got != "cli/2.0 (ci) "+DefaultUserAgent() {
+		t.Errorf("got %q", got)
+	}
+	if got := (UserAgent{}).String(); got != DefaultUserAgent() {
+		t.Errorf("empty product = %q", got)
+	}
+}
+
+func Test_Client_SetDefaultHeadersCanonical(t *testing.T) {
+	client := NewClient().SetDefaultHeaders(map[string]string{"user-agent": "custom"})
+	req, err := client.Get("http://example.com").buildRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ua := req.Header.Get("User-Agent"); ua != "custom" {
+		t.Errorf("User-Agent = %q, want custom", ua)
+	}
+}
@@ -0,0 +1,112 @@
+package vconfig
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chhz0/go-component-base/pkg/metrics"
+)
+
+// KeyAccess 记录某个配置 key 的读取情况
+type KeyAccess struct {
+	Key        string
+	Count      uint64
+	LastAccess time.Time // 从未读取时为零值
+}
+
+// accessAudit 记录配置 key 的读取次数与最近读取时间
+type accessAudit struct {
+	mu        sync.Mutex
+	keys      map[string]*keyAccess
+	collector *metrics.Collector
+}
+
+// keyAccess 缓存 key 对应的计数器，避免每次读取都重新注册
+type keyAccess struct {
+	KeyAccess
+	counter *metrics.CounterMetric
+}
+
+func newAccessAudit(collector *metrics.Collector) *accessAudit {
+	return &accessAudit{
+		keys:      make(map[string]*keyAccess),
+		collector: collector,
+	}
+}
+
+func (a *accessAudit) record(key string) {
+	// viper 的 key 不区分大小写，AllKeys 返回小写形式
+	key = strings.ToLower(key)
+
+	a.mu.Lock()
+	ka, ok := a.keys[key]
+	if !ok {
+		ka = &keyAccess{KeyAccess: KeyAccess{Key: key}}
+		if a.collector != nil {
+			name := accessMetricName(key)
+			a.collector.Register(metrics.NewCounter(name))
+			ka.counter, _ = a.collector.Get(name).(*metrics.CounterMetric)
+		}
+		a.keys[key] = ka
+	}
+	ka.Count++
+	ka.LastAccess = time.Now()
+	counter := ka.counter
+	a.mu.Unlock()
+
+	if counter != nil {
+		counter.Inc()
+	}
+}
+
+func (a *accessAudit) report(allKeys []string) []KeyAccess {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(a.keys))
+	report := make([]KeyAccess, 0, len(a.keys)+len(allKeys))
+	for key, ka := range a.keys {
+		seen[key] = struct{}{}
+		report = append(report, ka.KeyAccess)
+	}
+	// 从未被读取的 key 同样列出，便于发现无用配置
+	for _, key := range allKeys {
+		if _, ok := seen[key]; !ok {
+			report = append(report, KeyAccess{Key: key})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Count != report[j].Count {
+			return report[i].Count > report[j].Count
+		}
+		return report[i].Key < report[j].Key
+	})
+	return report
+}
+
+func accessMetricName(key string) string {
+	return "vconfig.access." + key
+}
+
+// recordAccess 在开启读取审计时记录 key 的访问
+func (vc *VConfig) recordAccess(key string) {
+	if vc.audit != nil {
+		vc.audit.record(key)
+	}
+}
+
+// AccessReport 返回配置 key 的读取统计，按读取次数降序排列
+// 包含已加载但从未被读取的 key (Count 为 0)
+// 未开启读取审计时返回 nil
+func (vc *VConfig) AccessReport() []KeyAccess {
+	if vc.audit == nil {
+		return nil
+	}
+	vc.mu.RLock()
+	keys := vc.v.AllKeys()
+	vc.mu.RUnlock()
+	return vc.audit.report(keys)
+}
@@ -0,0 +1,35 @@
+package vconfig
+
+import "sync"
+
+// registry 保存按名称注册的配置实例, 供由多个组件组成的应用共享
+var registry = struct {
+	mu      sync.RWMutex
+	configs map[string]*VConfig
+}{
+	configs: make(map[string]*VConfig),
+}
+
+// Register 以 name 注册配置实例, 同名实例将被替换
+func Register(name string, vc *VConfig) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.configs[name] = vc
+}
+
+// Use 返回以 name 注册的配置实例, 未注册时返回 nil
+func Use(name string) *VConfig {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.configs[name]
+}
+
+// Unregister 移除以 name 注册的配置实例
+func Unregister(name string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	delete(registry.configs, name)
+}
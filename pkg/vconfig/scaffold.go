@@ -0,0 +1,161 @@
+package vconfig
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// WriteDefault 将 UnmarshalPtr 对应结构体的默认配置写入 YAML 文件, 用于生成初始配置
+// 默认值为结构体零值合并 WithDefaults 设置的值, 字段上的 `comment` tag 写为注释
+// key 名依次取 mapstructure、yaml tag, 否则使用小写字段名
+// 文件已存在时返回 ErrConfigExists, 不会覆盖
+func (vc *VConfig) WriteDefault(path string) error {
+	if vc.opts.UnmarshalPtr == nil {
+		return ErrUnmarshalNil
+	}
+
+	typ := reflect.TypeOf(vc.opts.UnmarshalPtr)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s is not a struct", ErrInvalidType, typ)
+	}
+
+	ptr := reflect.New(typ)
+	dv := viper.New()
+	for k, v := range vc.opts.Defaults {
+		dv.SetDefault(k, v)
+	}
+	if err := dv.Unmarshal(ptr.Interface(), decodeHook); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnmarshal, err)
+	}
+
+	node, err := defaultNode(ptr.Elem(), map[reflect.Type]bool{})
+	if err != nil {
+		return err
+	}
+	buf, err := yamlv3.Marshal(&yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{node}})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%w: %s", ErrConfigExists, path)
+		}
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(buf)
+	return err
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// defaultNode 生成 v 对应的 YAML 节点, expanding 记录正在展开的结构体类型,
+// 自引用的 nil 指针 (如链表节点) 写为 null, 避免无限递归
+func defaultNode(v reflect.Value, expanding map[reflect.Type]bool) (*yamlv3.Node, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if expanding[v.Type().Elem()] {
+				return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}, nil
+			}
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+
+	// time.Duration 与 encoding.TextMarshaler (如 time.Time) 按标量写出
+	if d, ok := v.Interface().(time.Duration); ok {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: d.String()}, nil
+	}
+	if m, ok := textMarshaler(v); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: string(text)}, nil
+	}
+	if v.Kind() != reflect.Struct {
+		node := &yamlv3.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	mapping := &yamlv3.Node{Kind: yamlv3.MappingNode}
+	typ := v.Type()
+	expanding[typ] = true
+	defer delete(expanding, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(field)
+		if name == "-" {
+			continue
+		}
+
+		value, err := defaultNode(v.Field(i), expanding)
+		if err != nil {
+			return nil, err
+		}
+		if squash && value.Kind == yamlv3.MappingNode {
+			mapping.Content = append(mapping.Content, value.Content...)
+			continue
+		}
+
+		key := &yamlv3.Node{
+			Kind:        yamlv3.ScalarNode,
+			Value:       name,
+			HeadComment: field.Tag.Get("comment"),
+		}
+		mapping.Content = append(mapping.Content, key, value)
+	}
+	return mapping, nil
+}
+
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	if v.Type().Implements(textMarshalerType) {
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		return m, ok
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		m, ok := v.Addr().Interface().(encoding.TextMarshaler)
+		return m, ok
+	}
+	return nil, false
+}
+
+// fieldKey 返回字段对应的配置 key, 以及是否需要展开到父级 (mapstructure:",squash")
+func fieldKey(field reflect.StructField) (string, bool) {
+	for _, tagName := range []string{"mapstructure", "yaml"} {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		squash := strings.Contains(opts, "squash") || strings.Contains(opts, "inline")
+		if name != "" || squash {
+			return name, squash
+		}
+	}
+	return strings.ToLower(field.Name), false
+}
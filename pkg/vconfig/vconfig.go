@@ -12,19 +12,26 @@
 package vconfig
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/chhz0/go-component-base/pkg/metrics"
 	"github.com/fsnotify/fsnotify"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
@@ -32,7 +39,9 @@ import (
 
 var (
 	ErrConfigNotFound = errors.New("config file not found")
-	ErrDotEnvNotFound = errors.New("dotenv file not found")
+	ErrDotEnvNotFound = fmt.Errorf("dotenv %w", ErrConfigNotFound)
+	ErrParse          = errors.New("config parse error")
+	ErrConfigExists   = errors.New("config file already exists")
 	ErrReaderIO       = errors.New("reader io error")
 	ErrInvalidType    = errors.New("invalid config type")
 	ErrRemoteConfig   = errors.New("remote config error")
@@ -56,10 +65,17 @@ type Env struct {
 
 // TODO: 多配置文件来源
 type Local struct {
-	ConfigName  string    // 配置文件名
-	ConfigType  string    // 配置文件类型
-	ConfigPaths []string  // 配置文件路径
-	ConfigIO    io.Reader // 配置读取 IO
+	ConfigName  string   // 配置文件名
+	ConfigType  string   // 配置文件类型
+	ConfigPaths []string // 配置文件路径
+	// ConfigIO/ConfigFS 内置配置, 例如通过 go:embed 嵌入的默认配置
+	// 设置后作为基础配置读取, 本地配置文件存在时合并覆盖
+	ConfigIO     io.Reader // 配置读取 IO
+	ConfigFS     fs.FS     // 配置读取 FS, 优先于 ConfigIO
+	ConfigFSPath string    // ConfigFS 中的配置文件路径, 未设置 ConfigType 时根据扩展名推断
+	// Mode 运行模式, 设置后在 <ConfigName>.yaml 之上合并 <ConfigName>.<Mode>.yaml
+	// 查找顺序: <ConfigName>.<Mode> > <ConfigName> > default, 任一文件缺失不视为错误
+	Mode string
 }
 
 type Options struct {
@@ -82,16 +98,26 @@ type Options struct {
 	RemoteWatch         bool
 	RemoteWatchInterval time.Duration
 
+	// AccessCollector 开启读取审计时, 额外将读取次数记录到 metrics.Collector
+	AccessCollector *metrics.Collector
+
 	EnableEnv    bool // 是否开启环境变量
 	EnableFlag   bool // 是否使用flag
 	EnableRemote bool // 是否开启远程配置中心
+	EnableAudit  bool // 是否开启配置读取审计
 }
 
 type VConfig struct {
-	v    *viper.Viper
-	vps  map[string]*viper.Viper
-	opts *Options
-	mu   sync.RWMutex
+	v     *viper.Viper
+	vps   map[string]*viper.Viper
+	opts  *Options
+	audit *accessAudit
+	// ioData 缓存 Local.ConfigIO 的内容
+	ioData []byte
+	// sets/flags 记录 Set 的 key 与绑定的 flag, 查找时优先于 dotenv 与 env
+	sets  map[string]struct{}
+	flags map[string]*pflag.Flag
+	mu    sync.RWMutex
 }
 
 // New 使用 options 模式创建配置实例
@@ -136,6 +162,15 @@ func New(opts *Options) *VConfig {
 }
 
 func (vc *VConfig) initialize() {
+	if vc.vps == nil {
+		vc.vps = make(map[string]*viper.Viper)
+	}
+	vc.sets = make(map[string]struct{})
+	vc.flags = make(map[string]*pflag.Flag)
+	if vc.opts.EnableAudit {
+		vc.audit = newAccessAudit(vc.opts.AccessCollector)
+	}
+
 	vc.setDefault()
 
 	// 加载 flag 参数
@@ -155,7 +190,7 @@ func (vc *VConfig) initialize() {
 
 	if vc.opts.DotEnv != nil {
 		if err := vc.mergeLocal(); err != nil && !errors.Is(err, ErrConfigNotFound) {
-			log.Printf("Warning: Error loading local file: %v", err)
+			log.Printf("Warning: Error loading dotenv file: %v", err)
 		}
 	}
 
@@ -169,6 +204,7 @@ func (vc *VConfig) initialize() {
 	// 加载 key/value 参数
 	for key, val := range vc.opts.Sets {
 		vc.v.Set(key, val)
+		vc.sets[strings.ToLower(key)] = struct{}{}
 	}
 }
 
@@ -192,32 +228,93 @@ func (vc *VConfig) bindFlags() {
 		fs.VisitAll(func(f *pflag.Flag) {
 			if err := vc.v.BindPFlag(f.Name, f); err != nil {
 				log.Printf("failed to bind flag %s: %v", f.Name, err)
+				return
 			}
+			vc.flags[strings.ToLower(f.Name)] = f
 		})
 	}
 }
 
 func (vc *VConfig) loadLocal() error {
+	err := vc.readLocal()
+	if vc.opts.Local.Mode == "" {
+		return err
+	}
+
+	if merr := vc.mergeMode(); merr != nil {
+		if errors.Is(merr, ErrConfigNotFound) {
+			return err
+		}
+		return merr
+	}
+	// 模式配置已加载, 基础配置缺失时回退到默认值
+	if errors.Is(err, ErrConfigNotFound) {
+		return nil
+	}
+	return err
+}
+
+// mergeMode 读取 <ConfigName>.<Mode> 配置文件并合并到主配置中
+func (vc *VConfig) mergeMode() error {
+	local := vc.opts.Local
+	name := local.ConfigName
+	if name == "" {
+		name = "config"
+	}
+
+	mv := viper.New()
+	mv.SetConfigName(name + "." + local.Mode)
+	mv.SetConfigType(local.ConfigType)
+	for _, cp := range local.ConfigPaths {
+		mv.AddConfigPath(cp)
+	}
+	if err := mv.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok || os.IsNotExist(err) {
+			return fmt.Errorf("%w: %v", ErrConfigNotFound, err)
+		}
+		return fmt.Errorf("%w: mode %s: %v", ErrParse, local.Mode, err)
+	}
+
+	return vc.mergeFromViper(mv)
+}
+
+func (vc *VConfig) readLocal() error {
 	vc.setInRead("local")
+	if vc.opts.Local.ConfigIO != nil || vc.opts.Local.ConfigFS != nil {
+		return vc.loadReaderIO()
+	}
+
 	if err := vc.v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok && vc.opts.Local.ConfigIO != nil {
-			return vc.loadReaderIO()
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return fmt.Errorf("%w: %v", ErrConfigNotFound, err)
 		}
-		return fmt.Errorf("config file read error: %v\n", err)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %v", ErrConfigNotFound, err)
+		}
+		return fmt.Errorf("%w: %v", ErrParse, err)
 	}
 
 	return nil
 }
 
+// mergeLocal 使用独立的 viper 读取 dotenv 文件, 保留在 vps["dotenv"] 中供统一查找使用,
+// 并合并到主配置中
 func (vc *VConfig) mergeLocal() error {
-	vc.setInRead("dotenv")
-	if err := vc.v.MergeInConfig(); err != nil {
-		if os.IsNotExist(err) {
+	dv := viper.New()
+	dv.SetConfigName(vc.opts.DotEnv.ConfigName)
+	dv.SetConfigType(vc.opts.DotEnv.ConfigType)
+	for _, cp := range vc.opts.DotEnv.ConfigPaths {
+		dv.AddConfigPath(cp)
+	}
+	if err := dv.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok || os.IsNotExist(err) {
 			return ErrDotEnvNotFound
 		}
-		return fmt.Errorf("dotenv file merge error: %v\n", err)
+		return fmt.Errorf("%w: dotenv: %v", ErrParse, err)
 	}
-	return nil
+
+	vc.vps["dotenv"] = dv
+	return vc.mergeFromViper(dv)
 }
 
 func (vc *VConfig) mergeFromViper(vp *viper.Viper) error {
@@ -237,14 +334,51 @@ func (vc *VConfig) setInRead(in string) {
 	}
 }
 
+// loadReaderIO 读取内置配置 (ConfigIO/ConfigFS) 作为基础配置, 存在本地配置文件时合并覆盖
 func (vc *VConfig) loadReaderIO() error {
-	if err := vc.v.ReadConfig(vc.opts.Local.ConfigIO); err != nil {
-		return ErrReaderIO
+	local := vc.opts.Local
+	data, err := vc.readerIOData()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReaderIO, err)
 	}
 
+	if local.ConfigType == "" {
+		configType := strings.TrimPrefix(path.Ext(local.ConfigFSPath), ".")
+		if configType == "" {
+			configType = "yaml"
+		}
+		vc.v.SetConfigType(configType)
+	}
+	if err := vc.v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	if err := vc.v.MergeInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok || os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrParse, err)
+	}
 	return nil
 }
 
+// readerIOData 返回内置配置内容, ConfigIO 只能读取一次, 因此缓存读取结果供重新加载使用
+func (vc *VConfig) readerIOData() ([]byte, error) {
+	local := vc.opts.Local
+	if local.ConfigFS != nil {
+		return fs.ReadFile(local.ConfigFS, local.ConfigFSPath)
+	}
+
+	if vc.ioData == nil {
+		data, err := io.ReadAll(local.ConfigIO)
+		if err != nil {
+			return nil, err
+		}
+		vc.ioData = data
+	}
+	return vc.ioData, nil
+}
+
 func (vc *VConfig) loadRemote() error {
 	if vc.opts.Remote == nil {
 		return ErrRemoteConfig
@@ -264,27 +398,139 @@ func (vc *VConfig) loadRemote() error {
 	return nil
 }
 
+// Load 重新读取本地配置文件
+// 文件不存在时返回 ErrConfigNotFound, 解析失败时返回 ErrParse, 可通过 errors.Is 判断
+func (vc *VConfig) Load() error {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return vc.loadLocal()
+}
+
+// LoadDotEnv 重新读取 dotenv 文件并合并到配置中
+// 未配置 dotenv 或文件不存在时返回 ErrDotEnvNotFound (同时满足 errors.Is(err, ErrConfigNotFound))
+func (vc *VConfig) LoadDotEnv() error {
+	if vc.opts.DotEnv == nil {
+		return ErrDotEnvNotFound
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return vc.mergeLocal()
+}
+
+// MustLoad 同 Load, 出错时 panic
+func (vc *VConfig) MustLoad() {
+	if err := vc.Load(); err != nil {
+		log.Panicf("load config: %v", err)
+	}
+}
+
+// MustLoadDotEnv 同 LoadDotEnv, 出错时 panic
+func (vc *VConfig) MustLoadDotEnv() {
+	if err := vc.LoadDotEnv(); err != nil {
+		log.Panicf("load dotenv: %v", err)
+	}
+}
+
 // Watcher 监听配置文件变化, changedFunc 将在配置文件更新并重新加载完成后调用
 func (vc *VConfig) Watcher(changedFunc func()) {
-	vc.enableWatch(changedFunc)
+	vc.enableWatch(func(_, _ map[string]any) {
+		changedFunc()
+	})
+}
+
+// OnChange 监听配置文件变化, 配置重新加载并反序列化到 UnmarshalPtr 后调用 fn,
+// oldSettings/newSettings 分别为变更前后的 AllSettings
+func (vc *VConfig) OnChange(fn func(oldSettings, newSettings map[string]any)) {
+	vc.enableWatch(fn)
 }
 
-func (vc *VConfig) enableWatch(fn func()) {
-	vc.v.OnConfigChange(func(in fsnotify.Event) {
-		log.Printf("config file changed: %v\n", in.Name)
-		if err := vc.v.ReadInConfig(); err != nil {
+func (vc *VConfig) enableWatch(fn func(oldSettings, newSettings map[string]any)) {
+	lastSettings := vc.AllSettings()
+	vc.watchConfig(func(name string) {
+		log.Printf("config file changed: %v\n", name)
+		if err := vc.reload(); err != nil {
 			log.Printf("reload config file error: %v\n", err)
 		}
 		_ = vc.unmarshal()
-		fn()
+
+		oldSettings, newSettings := lastSettings, vc.AllSettings()
+		lastSettings = newSettings
+		fn(oldSettings, newSettings)
 	})
-	vc.v.WatchConfig()
 
 	if vc.opts.RemoteWatch {
 		go vc.watchRemote(context.Background())
 	}
 }
 
+// watchConfig 监听配置文件所在目录, 配置文件写入、创建或其符号链接目标变化 (如 k8s ConfigMap 替换) 时调用 onChange.
+// 与 viper.WatchConfig 不同, 不在锁外重新读取配置, 重新读取统一由 reload 在写锁内完成
+func (vc *VConfig) watchConfig(onChange func(name string)) {
+	vc.mu.RLock()
+	filename := vc.v.ConfigFileUsed()
+	vc.mu.RUnlock()
+	if filename == "" {
+		log.Printf("watch config: no config file in use\n")
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("watch config: %v\n", err)
+		return
+	}
+	configFile := filepath.Clean(filename)
+	realConfigFile, _ := filepath.EvalSymlinks(filename)
+	// 监听整个目录以感知重命名方式的原子保存
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		watcher.Close()
+		log.Printf("watch config: %v\n", err)
+		return
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				currentConfigFile, _ := filepath.EvalSymlinks(filename)
+				if (filepath.Clean(event.Name) == configFile &&
+					(event.Has(fsnotify.Write) || event.Has(fsnotify.Create))) ||
+					(currentConfigFile != "" && currentConfigFile != realConfigFile) {
+					realConfigFile = currentConfigFile
+					onChange(event.Name)
+				} else if filepath.Clean(event.Name) == configFile && event.Has(fsnotify.Remove) {
+					return
+				}
+			case err, ok := <-watcher.Errors:
+				if ok {
+					log.Printf("watch config: %v\n", err)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// reload 重新读取本地配置文件, 并重新合并模式配置与 dotenv
+func (vc *VConfig) reload() error {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	if err := vc.loadLocal(); err != nil {
+		return err
+	}
+	if vc.opts.DotEnv != nil {
+		if err := vc.mergeLocal(); err != nil && !errors.Is(err, ErrConfigNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (vc *VConfig) watchRemote(ctx context.Context) {
 	ticker := time.NewTicker(vc.opts.RemoteWatchInterval)
 	defer ticker.Stop()
@@ -294,15 +540,27 @@ func (vc *VConfig) watchRemote(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := vc.v.WatchRemoteConfig(); err != nil {
+			vc.mu.Lock()
+			err := vc.v.WatchRemoteConfig()
+			vc.mu.Unlock()
+			if err != nil {
 				log.Printf("reload remote config error: %v\n", err)
 			}
 		}
 	}
 }
 
+// decodeHook 在 viper 默认的 duration 与 slice 转换之外, 支持 encoding.TextUnmarshaler (如 time.Time)
+var decodeHook = viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	mapstructure.TextUnmarshallerHookFunc(),
+))
+
 func (vc *VConfig) Unmarshal(ptr any) error {
-	if err := vc.v.Unmarshal(ptr); err != nil {
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+	if err := vc.v.Unmarshal(ptr, decodeHook); err != nil {
 		return ErrUnmarshal
 	}
 
@@ -313,32 +571,39 @@ func (vc *VConfig) unmarshal() error {
 	if vc.opts.UnmarshalPtr == nil {
 		return ErrUnmarshalNil
 	}
-	if err := vc.v.Unmarshal(vc.opts.UnmarshalPtr); err != nil {
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+	if err := vc.v.Unmarshal(vc.opts.UnmarshalPtr, decodeHook); err != nil {
 		return ErrUnmarshal
 	}
 
 	return nil
 }
 
-// Marshal 将vc.v.AllSettings()序列化为字符串
-// 目前支持：json, yaml, toml
-func (vc *VConfig) MarshalToString(marshalType string) (string, error) {
-	m := vc.v.AllSettings()
-	var buf []byte
-	var err error
-	switch marshalType {
+// Marshal 将vc.v.AllSettings()序列化为指定格式
+// 目前支持：json, yaml, toml, 其他格式返回 ErrInvalidType
+func (vc *VConfig) Marshal(format string) ([]byte, error) {
+	m := vc.AllSettings()
+	switch format {
 	case "json":
-		buf, err = json.Marshal(m)
+		return json.Marshal(m)
 	case "yaml":
-		buf, err = yaml.Marshal(m)
+		return yaml.Marshal(m)
 	case "toml":
-		buf, err = toml.Marshal(m)
+		return toml.Marshal(m)
 	}
+	return nil, fmt.Errorf("%w: %s", ErrInvalidType, format)
+}
+
+// MarshalToString 将vc.v.AllSettings()序列化为字符串, 支持的格式同 Marshal
+func (vc *VConfig) MarshalToString(marshalType string) (string, error) {
+	buf, err := vc.Marshal(marshalType)
 	if err != nil {
 		return "", err
 	}
 	return string(buf), nil
 }
+
 func (vc *VConfig) setDefault() {
 	for k, v := range vc.opts.Defaults {
 		vc.v.SetDefault(k, v)
@@ -346,24 +611,40 @@ func (vc *VConfig) setDefault() {
 }
 
 func (vc *VConfig) BindPFlag(mFlag map[string]*pflag.Flag) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
 	for key, flag := range mFlag {
-		_ = vc.v.BindPFlag(key, flag)
+		if vc.v.BindPFlag(key, flag) == nil {
+			vc.flags[strings.ToLower(key)] = flag
+		}
 	}
 }
 
 func (vc *VConfig) BindPFlags(pfs ...*pflag.FlagSet) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
 	for _, pf := range pfs {
-		_ = vc.v.BindPFlags(pf)
+		if vc.v.BindPFlags(pf) != nil {
+			continue
+		}
+		pf.VisitAll(func(f *pflag.Flag) {
+			vc.flags[strings.ToLower(f.Name)] = f
+		})
 	}
 }
 
 // BindEnvs 绑定环境变量，不同于viper.BindEnv限制一个传入的参数
 // 如果想使用viper.BindEnv，请调用函数 V() 获取 *viper.Viper实例
 func (vc *VConfig) BindEnvs(input string) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
 	_ = vc.v.BindEnv(input)
 }
 
 func (vc *VConfig) GetEnv(key string) string {
+	vc.recordAccess(key)
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
 	return vc.v.GetString(key)
 }
 
@@ -371,24 +652,135 @@ func (vc *VConfig) Set(key string, value any) {
 	vc.mu.Lock()
 	defer vc.mu.Unlock()
 	vc.v.Set(key, value)
+	vc.sets[strings.ToLower(key)] = struct{}{}
 }
 
 // Get 允许访问给定key 的value
 // 如果有嵌套的key，则使用点号分隔符访问："section.key"
+// 查找顺序：set > flag > dotenv > env > viper(config > key/value > default)
+// dotenv 与 env 中的 key 按环境变量规则转换, 如 "server.port" => "<PREFIX>_SERVER_PORT"
 func (vc *VConfig) Get(key string) (any, bool) {
+	vc.recordAccess(key)
+	return vc.lookup(key)
+}
+
+// GetString 与 Get 查找顺序一致, 不存在时返回空字符串
+func (vc *VConfig) GetString(key string) string {
+	vc.recordAccess(key)
+	v, _ := vc.lookup(key)
+	return cast.ToString(v)
+}
+
+// GetInt 与 Get 查找顺序一致, 不存在或无法转换时返回 0
+func (vc *VConfig) GetInt(key string) int {
+	vc.recordAccess(key)
+	v, _ := vc.lookup(key)
+	return cast.ToInt(v)
+}
+
+// GetBool 与 Get 查找顺序一致, 不存在或无法转换时返回 false
+func (vc *VConfig) GetBool(key string) bool {
+	vc.recordAccess(key)
+	v, _ := vc.lookup(key)
+	return cast.ToBool(v)
+}
+
+// GetDuration 与 Get 查找顺序一致, 支持 "5s" 形式的字符串, 不存在或无法转换时返回 0
+func (vc *VConfig) GetDuration(key string) time.Duration {
+	vc.recordAccess(key)
+	v, _ := vc.lookup(key)
+	return cast.ToDuration(v)
+}
+
+// GetStringSlice 与 Get 查找顺序一致, 字符串值按空白分割
+func (vc *VConfig) GetStringSlice(key string) []string {
+	vc.recordAccess(key)
+	v, _ := vc.lookup(key)
+	return cast.ToStringSlice(v)
+}
+
+// IsSet 判断 key 是否存在于任一配置来源中
+func (vc *VConfig) IsSet(key string) bool {
+	_, ok := vc.lookup(key)
+	return ok
+}
+
+func (vc *VConfig) lookup(key string) (any, bool) {
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+
+	// Set 与已修改的 flag 优先于 dotenv 与 env, 与 viper 的优先级一致
+	if vc.overridden(key) {
+		return vc.v.Get(key), true
+	}
+
+	if dv, ok := vc.vps["dotenv"]; ok {
+		for _, k := range vc.envKeys(key) {
+			if dv.IsSet(k) {
+				return dv.Get(k), true
+			}
+		}
+	}
+
+	if vc.opts.EnableEnv {
+		for _, k := range vc.envKeys(key) {
+			if v, ok := os.LookupEnv(strings.ToUpper(k)); ok {
+				return v, true
+			}
+		}
+	}
+
 	if !vc.v.IsSet(key) {
 		return nil, false
 	}
+	return vc.v.Get(key), true
+}
+
+// overridden 判断 key 或其上级 key 是否通过 Set 设置, 或绑定的 flag 已在命令行中修改
+func (vc *VConfig) overridden(key string) bool {
+	key = strings.ToLower(key)
+	if f, ok := vc.flags[key]; ok && f.Changed {
+		return true
+	}
+	for k := key; ; {
+		if _, ok := vc.sets[k]; ok {
+			return true
+		}
+		i := strings.LastIndex(k, ".")
+		if i < 0 {
+			return false
+		}
+		k = k[:i]
+	}
+}
+
+// envKeys 返回 key 对应的环境变量名候选 (小写), 依次为替换后的名称与原始名称
+func (vc *VConfig) envKeys(key string) []string {
+	raw := key
+	if vc.opts.Env != nil && vc.opts.Env.Prefix != "" {
+		raw = vc.opts.Env.Prefix + "_" + key
+	}
+	raw = strings.ToLower(raw)
 
-	v := vc.v.Get(key)
-	return v, true
+	replaced := raw
+	if vc.opts.Env != nil && vc.opts.Env.KeyReplacer != nil {
+		replaced = vc.opts.Env.KeyReplacer.Replace(raw)
+	}
+	if replaced == raw {
+		return []string{raw}
+	}
+	return []string{replaced, raw}
 }
 
+// AllSettings 返回合并后的全部配置
 func (vc *VConfig) AllSettings() map[string]any {
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
 	return vc.v.AllSettings()
 }
 
 // V returns the viper instance
+// 直接使用 viper 实例不受 vc 的锁保护, 开启热更新时不应与重新加载并发读写
 func (vc *VConfig) V() *viper.Viper {
 	return vc.v
 }
@@ -425,12 +817,35 @@ func WithConfigName(name string) func(*Options) {
 	}
 }
 
+// WithConfigReader 设置内置配置, 本地配置文件存在时合并覆盖
+// 配置格式由 ConfigType 决定, 未设置时默认为 yaml
+func WithConfigReader(r io.Reader) func(*Options) {
+	return func(o *Options) {
+		o.Local.ConfigIO = r
+	}
+}
+
+// WithConfigFS 从 fs.FS (如 embed.FS) 读取内置配置, 本地配置文件存在时合并覆盖
+func WithConfigFS(fsys fs.FS, path string) func(*Options) {
+	return func(o *Options) {
+		o.Local.ConfigFS = fsys
+		o.Local.ConfigFSPath = path
+	}
+}
+
 func WithConfigType(configType string) func(*Options) {
 	return func(o *Options) {
 		o.Local.ConfigType = configType
 	}
 }
 
+// WithMode 设置运行模式, 在基础配置文件之上合并 <ConfigName>.<mode> 配置文件
+func WithMode(mode string) func(*Options) {
+	return func(o *Options) {
+		o.Local.Mode = mode
+	}
+}
+
 func WithConfigPaths(paths ...string) func(*Options) {
 	return func(o *Options) {
 		o.Local.ConfigPaths = append(o.Local.ConfigPaths, paths...)
@@ -502,6 +917,17 @@ func EnableRemoteWatch(enable bool) func(*Options) {
 	}
 }
 
+// EnableAccessAudit 开启配置读取审计, 通过 AccessReport 查看 key 的读取次数与最近读取时间
+// 可选传入 metrics.Collector, 读取次数同时记录为 "vconfig.access.<key>" 计数器
+func EnableAccessAudit(collector ...*metrics.Collector) func(*Options) {
+	return func(o *Options) {
+		o.EnableAudit = true
+		if len(collector) > 0 {
+			o.AccessCollector = collector[0]
+		}
+	}
+}
+
 func defaultKeyReplacer() *strings.Replacer {
 	return strings.NewReplacer(".", "_", "-", "_")
 }
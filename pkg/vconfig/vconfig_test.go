@@ -1,8 +1,16 @@
 package vconfig
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/chhz0/go-component-base/pkg/metrics"
 	"github.com/spf13/pflag"
 )
 
@@ -171,3 +179,413 @@ func Test_VConfig_Default(t *testing.T) {
 func Test_VConfig_KeyValue(t *testing.T) {
 	// TODO: to be done
 }
+
+func Test_VConfig_AccessReport(t *testing.T) {
+	collector := metrics.NewCollector()
+	config := NewWith(
+		WithSets(map[string]any{
+			"app":  "vconfig_audit",
+			"dead": "never read",
+		}),
+		EnableAccessAudit(collector),
+	)
+
+	for i := 0; i < 2; i++ {
+		config.Get("app")
+	}
+	// key 不区分大小写，与 AllKeys 合并为同一项
+	config.Get("APP")
+
+	report := config.AccessReport()
+	if len(report) < 2 {
+		t.Fatalf("expected at least 2 keys in report, got %v", report)
+	}
+	if report[0].Key != "app" || report[0].Count != 3 || report[0].LastAccess.IsZero() {
+		t.Errorf("unexpected hot key: %+v", report[0])
+	}
+	for _, ka := range report {
+		if ka.Key == "dead" && ka.Count != 0 {
+			t.Errorf("dead key should not be read: %+v", ka)
+		}
+	}
+	if got := collector.Get("vconfig.access.app").Value(); got != uint64(3) {
+		t.Errorf("expected collector count 3, got %v", got)
+	}
+	for _, ka := range report {
+		if ka.Key == "APP" {
+			t.Errorf("expected keys to be lowercased, got %+v", ka)
+		}
+	}
+
+	if NewWith().AccessReport() != nil {
+		t.Error("expected nil report when audit is disabled")
+	}
+}
+
+func Test_VConfig_UnifiedLookup(t *testing.T) {
+	t.Setenv("VCONFIG_APP", "vconfig_env")
+	t.Setenv("VCONFIG_SERVER_MODE", "env::debug")
+
+	config := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "config",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{"./config"},
+		}),
+		WithDotEnv("dev", "."),
+		WithEnvPrefix("VCONFIG"),
+	)
+
+	// dotenv > env
+	if got := config.GetString("app"); got != "vconfig_DOTENV" {
+		t.Errorf("app: expected dotenv value, got %q", got)
+	}
+	if got := config.GetString("server.host"); got != "DOTENV::0.0.0.0" {
+		t.Errorf("server.host: expected dotenv value, got %q", got)
+	}
+	// env > config
+	if got := config.GetString("server.mode"); got != "env::debug" {
+		t.Errorf("server.mode: expected env value, got %q", got)
+	}
+	if _, ok := config.Get("not.exist"); ok {
+		t.Error("expected missing key")
+	}
+}
+
+func Test_VConfig_LookupPriority(t *testing.T) {
+	t.Setenv("VCONFIG_APP", "vconfig_env")
+	t.Setenv("VCONFIG_SERVER_HOST", "env::127.0.0.1")
+	t.Setenv("VCONFIG_SERVER_PORT", "3333")
+
+	flags := pflag.NewFlagSet("vconfig_test", pflag.ContinueOnError)
+	flags.String("server.host", "flag::default", "Server host")
+	flags.String("server.port", "2222", "Server port")
+	if err := flags.Parse([]string{"--server.host=flag::127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := NewWith(
+		WithDotEnv("dev", "."),
+		WithEnvPrefix("VCONFIG"),
+		EnableFlag(flags),
+	)
+	config.Set("app", "vconfig_set")
+
+	// set > dotenv > env
+	if got := config.GetString("app"); got != "vconfig_set" {
+		t.Errorf("app: expected set value, got %q", got)
+	}
+	// changed flag > dotenv > env
+	if got := config.GetString("server.host"); got != "flag::127.0.0.1" {
+		t.Errorf("server.host: expected flag value, got %q", got)
+	}
+	// unchanged flag < dotenv
+	if got := config.GetString("server.port"); got != "8080" {
+		t.Errorf("server.port: expected dotenv value, got %q", got)
+	}
+}
+
+func Test_VConfig_Load(t *testing.T) {
+	config := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "config",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{"./config"},
+		}),
+		WithDotEnv("dev", "."),
+	)
+	if err := config.Load(); err != nil {
+		t.Errorf("Load: %v", err)
+	}
+	if err := config.LoadDotEnv(); err != nil {
+		t.Errorf("LoadDotEnv: %v", err)
+	}
+
+	missing := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "missing",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{"./config"},
+		}),
+		WithDotEnv("missing", "."),
+	)
+	if err := missing.Load(); !errors.Is(err, ErrConfigNotFound) {
+		t.Errorf("expected ErrConfigNotFound, got %v", err)
+	}
+	if err := missing.LoadDotEnv(); !errors.Is(err, ErrDotEnvNotFound) || !errors.Is(err, ErrConfigNotFound) {
+		t.Errorf("expected ErrDotEnvNotFound, got %v", err)
+	}
+
+	broken := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "broken",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{"./testdata"},
+		}),
+	)
+	if err := broken.Load(); !errors.Is(err, ErrParse) {
+		t.Errorf("expected ErrParse, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustLoad to panic")
+		}
+	}()
+	missing.MustLoad()
+}
+
+func Test_VConfig_Mode(t *testing.T) {
+	newConfig := func(mode string) *VConfig {
+		return NewWith(
+			WithDefaults(map[string]any{"server.timeout": "5s"}),
+			WithLocal(&Local{
+				ConfigName:  "config",
+				ConfigType:  "yaml",
+				ConfigPaths: []string{"./config"},
+			}),
+			WithMode(mode),
+		)
+	}
+
+	prod := newConfig("prod")
+	if got := prod.GetString("server.port"); got != "2112" {
+		t.Errorf("server.port: expected mode override, got %q", got)
+	}
+	if got := prod.GetString("server.host"); got != "config::127.1.1.1" {
+		t.Errorf("server.host: expected base config, got %q", got)
+	}
+	if got := prod.GetString("server.timeout"); got != "5s" {
+		t.Errorf("server.timeout: expected default, got %q", got)
+	}
+
+	staging := newConfig("staging")
+	if err := staging.Load(); err != nil {
+		t.Errorf("missing mode override should not fail: %v", err)
+	}
+	if got := staging.GetString("server.port"); got != "1112" {
+		t.Errorf("server.port: expected base config, got %q", got)
+	}
+}
+
+func Test_VConfig_OnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("app: before\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg Config
+	config := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "config",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{dir},
+		}),
+		WithUnmarshal(&cfg),
+	)
+
+	changed := make(chan [2]any, 1)
+	config.OnChange(func(oldSettings, newSettings map[string]any) {
+		select {
+		case changed <- [2]any{oldSettings["app"], newSettings["app"]}:
+		default:
+		}
+	})
+
+	if err := os.WriteFile(file, []byte("app: after\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-changed:
+		if got[0] != "before" || got[1] != "after" {
+			t.Errorf("unexpected change: %v", got)
+		}
+		if cfg.App != "after" {
+			t.Errorf("expected UnmarshalPtr to be refreshed, got %q", cfg.App)
+		}
+	case <-time.After(3 * time.Second):
+		t.Skip("no fsnotify event received")
+	}
+}
+
+func Test_VConfig_ConcurrentReload(t *testing.T) {
+	config := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "config",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{"./config"},
+		}),
+		WithDotEnv("dev", "."),
+		WithEnvPrefix("VCONFIG"),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if err := config.reload(); err != nil {
+				t.Errorf("reload: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = config.GetString("server.host")
+			_ = config.AllSettings()
+		}
+	}()
+	wg.Wait()
+}
+
+func Test_VConfig_ConfigFS(t *testing.T) {
+	embedded := fstest.MapFS{
+		"default.yaml": &fstest.MapFile{Data: []byte("app: embedded\nserver:\n  mode: release\n")},
+	}
+
+	config := NewWith(
+		WithLocal(&Local{
+			ConfigName:  "config",
+			ConfigPaths: []string{"./config"},
+		}),
+		WithConfigFS(embedded, "default.yaml"),
+	)
+	if err := config.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := config.GetString("app"); got != "vconfig_config" {
+		t.Errorf("app: expected file override, got %q", got)
+	}
+	if got := config.GetString("server.mode"); got != "release" {
+		t.Errorf("server.mode: expected embedded value, got %q", got)
+	}
+
+	onlyReader := NewWith(
+		WithConfigName("missing"),
+		WithConfigReader(strings.NewReader("app: reader\n")),
+	)
+	if err := onlyReader.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := onlyReader.GetString("app"); got != "reader" {
+		t.Errorf("app: expected reader value, got %q", got)
+	}
+}
+
+func Test_VConfig_TypedGetters(t *testing.T) {
+	t.Setenv("VCONFIG_SERVER_DEBUG", "true")
+
+	config := NewWith(
+		WithDefaults(map[string]any{
+			"server.timeout": "1m30s",
+			"server.hosts":   []string{"a", "b"},
+		}),
+		WithLocal(&Local{
+			ConfigName:  "config",
+			ConfigType:  "yaml",
+			ConfigPaths: []string{"./config"},
+		}),
+		WithEnvPrefix("VCONFIG"),
+	)
+
+	if got := config.GetInt("server.port"); got != 1112 {
+		t.Errorf("GetInt: got %d", got)
+	}
+	if !config.GetBool("server.debug") {
+		t.Error("GetBool: expected true from env")
+	}
+	if got := config.GetDuration("server.timeout"); got != 90*time.Second {
+		t.Errorf("GetDuration: got %v", got)
+	}
+	if got := config.GetStringSlice("server.hosts"); len(got) != 2 || got[1] != "b" {
+		t.Errorf("GetStringSlice: got %v", got)
+	}
+	if !config.IsSet("server.port") || config.IsSet("server.missing") {
+		t.Error("IsSet: unexpected result")
+	}
+}
+
+func Test_VConfig_Registry(t *testing.T) {
+	db := NewWith(WithSets(map[string]any{"dsn": "mysql://db"}))
+	Register("db", db)
+	defer Unregister("db")
+
+	if Use("db") != db {
+		t.Fatal("expected registered instance")
+	}
+	if got := Use("db").GetString("dsn"); got != "mysql://db" {
+		t.Errorf("dsn: got %q", got)
+	}
+	if Use("cache") != nil {
+		t.Error("expected nil for unregistered name")
+	}
+}
+
+func Test_VConfig_Marshal(t *testing.T) {
+	config := NewWith(WithSets(map[string]any{"app": "vconfig_marshal"}))
+
+	for _, format := range []string{"json", "yaml", "toml"} {
+		buf, err := config.Marshal(format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !strings.Contains(string(buf), "vconfig_marshal") {
+			t.Errorf("%s: unexpected output %s", format, buf)
+		}
+	}
+
+	if _, err := config.Marshal("ini"); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("expected ErrInvalidType, got %v", err)
+	}
+}
+
+type scaffoldConfig struct {
+	App    string          `mapstructure:"app" comment:"application name"`
+	Server Server          `mapstructure:"server" comment:"http server"`
+	Wait   time.Duration   `mapstructure:"wait"`
+	Start  time.Time       `mapstructure:"start"`
+	Next   *scaffoldConfig `mapstructure:"next"`
+}
+
+func Test_VConfig_WriteDefault(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "conf", "config.yaml")
+	config := NewWith(
+		WithDefaults(map[string]any{"server.port": "8080", "wait": "5s"}),
+		WithUnmarshal(&scaffoldConfig{}),
+	)
+
+	if err := config.WriteDefault(file); err != nil {
+		t.Fatalf("WriteDefault: %v", err)
+	}
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# application name", "# http server", `port: "8080"`, "wait: 5s", `start: "0001-01-01T00:00:00Z"`, "next: null"} {
+		if !strings.Contains(string(buf), want) {
+			t.Errorf("expected %q in:\n%s", want, buf)
+		}
+	}
+
+	if err := config.WriteDefault(file); !errors.Is(err, ErrConfigExists) {
+		t.Errorf("expected ErrConfigExists, got %v", err)
+	}
+
+	// 生成的文件可以被重新读取
+	loaded := NewWith(WithLocal(&Local{
+		ConfigName:  "config",
+		ConfigType:  "yaml",
+		ConfigPaths: []string{filepath.Dir(file)},
+	}))
+	if got := loaded.GetDuration("wait"); got != 5*time.Second {
+		t.Errorf("wait: got %v", got)
+	}
+	var cfg scaffoldConfig
+	if err := loaded.Unmarshal(&cfg); err != nil {
+		t.Errorf("Unmarshal generated file: %v\n%s", err, buf)
+	}
+}
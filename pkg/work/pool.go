@@ -3,7 +3,6 @@ package work
 import (
 	"context"
 	"errors"
-	"fmt"
 	"sync"
 )
 
@@ -57,8 +56,7 @@ func (p *Pool) AddTask(t Executor) {
 
 func (p *Pool) startWorker(ctx context.Context) {
 	for i := 0; i < p.numWorkers; i++ {
-		go func(workerNum int) {
-			fmt.Printf("worker number %d started\n", workerNum)
+		go func() {
 			for {
 				select {
 				case task, ok := <-p.tasks:
@@ -74,6 +72,6 @@ func (p *Pool) startWorker(ctx context.Context) {
 					return
 				}
 			}
-		}(i)
+		}()
 	}
 }